@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	// -n/--namespace is registered per command by addNamespaceFlag, as a list.
+	configFlags.Namespace = nil
+	// --kubeconfig, --context, --cluster, --as, --as-group, --token, --server and the
+	// other kubectl connection flags, honored by every subcommand.
+	configFlags.AddFlags(RootCmd.PersistentFlags())
+	configFlags.WrapConfigFn = func(c *rest.Config) *rest.Config {
+		for _, wrap := range restConfigWrappers {
+			wrap(c)
+		}
+		return c
+	}
+}
+
+// restConfigWrappers adjust every rest config built from configFlags, e.g. for --debug.
+// They are added in RootCmd's PersistentPreRunE, before any client exists.
+var restConfigWrappers []func(*rest.Config)
+
+// newClientset builds a typed Kubernetes client from the kubeconfig-based flags.
+func newClientset(configFlags *genericclioptions.ConfigFlags) (kubernetes.Interface, error) {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	return clientset, nil
+}
+
+// addNamespaceFlag registers the shared -n/--namespace and -A/--all-namespaces flags on cmd.
+// what names the resources the namespaces filter, e.g. "pods".
+func addNamespaceFlag(cmd *cobra.Command, what string) {
+	cmd.Flags().StringSliceVarP(&namespaceFlag, "namespace", "n", nil,
+		fmt.Sprintf("Namespaces to filter %s, repeat the flag or separate with commas (-n ns1,ns2). Uses the current context's namespace if omitted.", what))
+	cmd.Flags().BoolVarP(&allNamespacesFlag, "all-namespaces", "A", false,
+		fmt.Sprintf("Search %s in all namespaces.", what))
+}
+
+// targetNamespaces returns the namespaces to query, without duplicates. Without -n this is
+// the namespace of the current kubeconfig context, unless -A or allNamespacesByDefault in
+// the config file ask for all of them. A single metav1.NamespaceAll ("") entry means all namespaces.
+func targetNamespaces(configFlags *genericclioptions.ConfigFlags) ([]string, error) {
+	if allNamespacesFlag {
+		if len(namespaceFlag) > 0 {
+			return nil, fmt.Errorf("-n/--namespace and -A/--all-namespaces can't be used together")
+		}
+		return []string{metav1.NamespaceAll}, nil
+	}
+
+	seen := map[string]bool{}
+	var namespaces []string
+	for _, ns := range namespaceFlag {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) > 0 {
+		return namespaces, nil
+	}
+
+	if helperConfig.AllNamespacesByDefault {
+		return []string{metav1.NamespaceAll}, nil
+	}
+	kubeconfigNamespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine namespace from kubeconfig: %w", err)
+	}
+	return []string{kubeconfigNamespace}, nil
+}
+
+// listAcrossNamespaces calls list once for every namespace returned by targetNamespaces
+// and concatenates the results. A failure is returned as a namespaceError.
+func listAcrossNamespaces[T any](configFlags *genericclioptions.ConfigFlags, list func(namespace string) ([]T, error)) ([]T, error) {
+	namespaces, err := targetNamespaces(configFlags)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	for _, ns := range namespaces {
+		nsItems, err := list(ns)
+		if err != nil {
+			return nil, &namespaceError{Namespace: ns, Err: err}
+		}
+		items = append(items, nsItems...)
+	}
+	return items, nil
+}
+
+// listNamespaceNames returns the names of all namespaces in the cluster.
+func listNamespaceNames(ctx context.Context, configFlags *genericclioptions.ConfigFlags) ([]string, error) {
+	clientset, err := newClientset(configFlags)
+	if err != nil {
+		return nil, err
+	}
+	list, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve namespaces: %w", err)
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
+
+// findPods returns the pods of the target namespaces whose name contains pattern.
+func findPods(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pattern string) ([]corev1.Pod, error) {
+	pods, err := listAcrossNamespaces(configFlags, func(ns string) ([]corev1.Pod, error) {
+		if cached, ok := daemonList[corev1.Pod](configFlags, "pods", ns); ok {
+			return cached, nil
+		}
+		list, err := clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+
+	var matching []corev1.Pod
+	for _, pod := range pods {
+		if matchesPattern(pod.Name, pattern) {
+			matching = append(matching, pod)
+		}
+	}
+	historyMatches = len(matching)
+	return matching, nil
+}
+
+// findSinglePod returns the only pod whose name contains pattern, and fails when the
+// pattern matches no pod or more than one.
+func findSinglePod(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pattern string) (*corev1.Pod, error) {
+	pods, err := findPods(ctx, configFlags, clientset, pattern)
+	if err != nil {
+		return nil, err
+	}
+	switch len(pods) {
+	case 0:
+		return nil, fmt.Errorf("no pods found matching the pattern: %s", pattern)
+	case 1:
+		return &pods[0], nil
+	}
+	// A full pod name wins over the other pods that merely contain it.
+	for i := range pods {
+		if pods[i].Name == pattern {
+			return &pods[i], nil
+		}
+	}
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	return nil, fmt.Errorf("pattern %q matches %d pods, please be more specific:\n  %s", pattern, len(pods), strings.Join(names, "\n  "))
+}
+
+// isForbidden reports whether err, or any error aggregated in it, is an authorization failure.
+func isForbidden(err error) bool {
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		for _, e := range agg.Errors() {
+			if isForbidden(e) {
+				return true
+			}
+		}
+		return false
+	}
+	return apierrors.IsForbidden(err)
+}
+
+// matchesPattern reports whether name contains pattern, ignoring case.
+// This is the same partial match the ip command uses for pod names.
+func matchesPattern(name, pattern string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(pattern))
+}
+
+// isPodReady reports whether the pod's Ready condition is True.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
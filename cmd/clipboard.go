@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands lists the clipboard tools tried on each OS, in order of preference.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip.exe"}},
+	"linux": {
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		// WSL can reach the Windows clipboard.
+		{"clip.exe"},
+	},
+}
+
+// copyToClipboard puts text onto the system clipboard using the first available tool.
+func copyToClipboard(text string) error {
+	candidates := clipboardCommands[runtime.GOOS]
+	if runtime.GOOS == "linux" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		// wl-copy hangs without a Wayland session, so X11 tools go first.
+		candidates = append(append([][]string{}, candidates[1:]...), candidates[0])
+	}
+
+	var tried []string
+	for _, c := range candidates {
+		path, err := exec.LookPath(c[0])
+		if err != nil {
+			tried = append(tried, c[0])
+			continue
+		}
+		copyCmd := exec.Command(path, c[1:]...)
+		copyCmd.Stdin = strings.NewReader(text)
+		if out, err := copyCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to copy to clipboard with %s: %w: %s", c[0], err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found (tried: %s)", strings.Join(tried, ", "))
+}
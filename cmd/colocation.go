@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ColocationInfo holds the co-location risk of one workload.
+type ColocationInfo struct {
+	Workload     string
+	Namespace    string
+	Replicas     int
+	Nodes        int
+	Zones        int
+	AntiAffinity string
+	Risk         string
+	Detail       string
+}
+
+// colocationCmd reports workloads whose replicas share a node or zone.
+var colocationCmd = &cobra.Command{
+	Use:   "colocation [SEARCH_PATTERN]",
+	Short: "Find replicas of workloads containing [SEARCH_PATTERN] that share a node or zone despite (or for lack of) anti-affinity.",
+	RunE:  runColocationFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(colocationCmd, "pods")
+}
+
+// runColocationFunc returns a function that groups the matching pods per workload and rates
+// how exposed each workload is to losing several replicas with a single node or zone.
+func runColocationFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper colocation web\nor:\n  kubectl helper colocation -n dev web")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+		nodes := make(map[string]*corev1.Node, len(nodeList.Items))
+		clusterZones := map[string]bool{}
+		for i := range nodeList.Items {
+			node := &nodeList.Items[i]
+			nodes[node.Name] = node
+			if zone := nodeZone(node); zone != "" {
+				clusterZones[zone] = true
+			}
+		}
+
+		resolver, err := newOwnerResolver(configFlags)
+		if err != nil {
+			return err
+		}
+		var rows []ColocationInfo
+		for _, w := range groupByWorkload(ctx, resolver, pods) {
+			// A single replica can't be co-located with itself.
+			if len(w.Pods) < 2 {
+				continue
+			}
+			rows = append(rows, colocationRisk(w, nodes, len(clusterZones)))
+		}
+		if len(rows) == 0 {
+			fmt.Printf("No workloads with more than one replica found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		printColocationTable(rows)
+		return nil
+	}
+}
+
+// antiAffinityScope describes the pod anti-affinity of the workload that applies to its own
+// pods, e.g. "required/node" or "preferred/zone", or "none".
+func antiAffinityScope(w *spreadWorkload) (scope string, required, perNode, perZone bool) {
+	a := w.Pods[0].Spec.Affinity
+	if a == nil || a.PodAntiAffinity == nil {
+		return "none", false, false, false
+	}
+
+	var terms []corev1.PodAffinityTerm
+	requiredTerms := len(a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	terms = append(terms, a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+	for _, pref := range a.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		terms = append(terms, pref.PodAffinityTerm)
+	}
+
+	var scopes []string
+	for i, term := range terms {
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		// Anti-affinity against other workloads doesn't keep these replicas apart.
+		if err != nil || !selector.Matches(labels.Set(w.Pods[0].Labels)) {
+			continue
+		}
+		kind := "preferred"
+		if i < requiredTerms {
+			kind = "required"
+			required = true
+		}
+		domain := term.TopologyKey
+		switch term.TopologyKey {
+		case corev1.LabelHostname:
+			domain = "node"
+			perNode = true
+		case corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone:
+			domain = "zone"
+			perZone = true
+		}
+		scopes = append(scopes, kind+"/"+domain)
+	}
+	if len(scopes) == 0 {
+		return "none", false, false, false
+	}
+	return strings.Join(scopes, ","), required, perNode, perZone
+}
+
+// colocationRisk rates one workload: HIGH when replicas share a node, MEDIUM when they share
+// a zone although more zones exist or when nothing spreads them, LOW otherwise.
+func colocationRisk(w *spreadWorkload, nodes map[string]*corev1.Node, clusterZoneCount int) ColocationInfo {
+	perNode := map[string]int{}
+	perZone := map[string]int{}
+	for _, pod := range w.Pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		perNode[pod.Spec.NodeName]++
+		if node, ok := nodes[pod.Spec.NodeName]; ok && nodeZone(node) != "" {
+			perZone[nodeZone(node)]++
+		}
+	}
+
+	scope, required, nodeScoped, zoneScoped := antiAffinityScope(w)
+	spreadConstraints := len(w.Pods[0].Spec.TopologySpreadConstraints) > 0
+	if scope == "none" && spreadConstraints {
+		scope = "none (spread constraints)"
+	}
+
+	info := ColocationInfo{
+		Workload:     strings.ToLower(w.Kind) + "/" + w.Name,
+		Namespace:    w.Namespace,
+		Replicas:     len(w.Pods),
+		Nodes:        len(perNode),
+		Zones:        len(perZone),
+		AntiAffinity: scope,
+		Risk:         "LOW",
+	}
+
+	var sharedNodes []string
+	for node, n := range perNode {
+		if n > 1 {
+			sharedNodes = append(sharedNodes, fmt.Sprintf("%d on %s", n, node))
+		}
+	}
+	sort.Strings(sharedNodes)
+
+	scheduled := 0
+	for _, n := range perNode {
+		scheduled += n
+	}
+	singleZone := scheduled > 1 && len(perZone) == 1 && clusterZoneCount > 1
+
+	switch {
+	case len(sharedNodes) > 0 && nodeScoped && !required:
+		info.Risk = "HIGH"
+		info.Detail = "preferred node anti-affinity not honored: " + strings.Join(sharedNodes, ", ")
+	case len(sharedNodes) > 0:
+		info.Risk = "HIGH"
+		info.Detail = "replicas share nodes: " + strings.Join(sharedNodes, ", ")
+	case singleZone && zoneScoped:
+		info.Risk = "MEDIUM"
+		info.Detail = "preferred zone anti-affinity not honored: all replicas in one zone"
+	case singleZone:
+		info.Risk = "MEDIUM"
+		info.Detail = "all replicas in one zone"
+	case scope == "none":
+		info.Risk = "MEDIUM"
+		info.Detail = "no anti-affinity or spread constraints, spread only by chance"
+	}
+	return info
+}
+
+// printColocationTable prints the workloads with the highest risk first.
+func printColocationTable(rows []ColocationInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	riskColors := map[string]*color.Color{
+		"HIGH":   color.New(color.FgRed, color.Bold),
+		"MEDIUM": color.New(color.FgYellow),
+		"LOW":    color.New(color.FgGreen),
+	}
+	riskOrder := map[string]int{"HIGH": 0, "MEDIUM": 1, "LOW": 2}
+
+	sort.SliceStable(rows, func(i, j int) bool { return riskOrder[rows[i].Risk] < riskOrder[rows[j].Risk] })
+
+	fmt.Println()
+	headerColor.Printf("%-40s %-15s %-9s %-6s %-6s %-25s %-7s %-50s\n",
+		"WORKLOAD", "NAMESPACE", "REPLICAS", "NODES", "ZONES", "ANTI-AFFINITY", "RISK", "DETAIL")
+	line := strings.Repeat("-", 165)
+	lineColor.Println(line)
+	for _, r := range rows {
+		fmt.Printf("%-40s %-15s %-9d %-6d %-6d %-25s ", r.Workload, r.Namespace, r.Replicas, r.Nodes, r.Zones, r.AntiAffinity)
+		riskColors[r.Risk].Printf("%-7s", r.Risk)
+		fmt.Printf(" %s\n", r.Detail)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestColocationRisk(t *testing.T) {
+	antiAffinity := func(required bool, topologyKey string) corev1.PodSpec {
+		term := corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			TopologyKey:   topologyKey,
+		}
+		a := &corev1.PodAntiAffinity{}
+		if required {
+			a.RequiredDuringSchedulingIgnoredDuringExecution = []corev1.PodAffinityTerm{term}
+		} else {
+			a.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}}
+		}
+		return corev1.PodSpec{Affinity: &corev1.Affinity{PodAntiAffinity: a}}
+	}
+	otherApp := antiAffinity(true, corev1.LabelHostname)
+	otherApp.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector.MatchLabels["app"] = "db"
+	spread := corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone}}}
+
+	nodes := testNodes(map[string]string{"n1": "a", "n2": "a", "n3": "b"})
+
+	tests := []struct {
+		name         string
+		workload     *spreadWorkload
+		zones        int
+		wantRisk     string
+		wantScope    string
+		wantDetail   string
+		wantNodes    int
+		wantZones    int
+		wantReplicas int
+	}{
+		{
+			name:         "spread over nodes and zones",
+			workload:     testWorkload(antiAffinity(true, corev1.LabelHostname), "n1", "n3"),
+			zones:        2,
+			wantRisk:     "LOW",
+			wantScope:    "required/node",
+			wantNodes:    2,
+			wantZones:    2,
+			wantReplicas: 2,
+		},
+		{
+			name:         "shared node",
+			workload:     testWorkload(antiAffinity(true, corev1.LabelHostname), "n1", "n1", "n3"),
+			zones:        2,
+			wantRisk:     "HIGH",
+			wantScope:    "required/node",
+			wantDetail:   "replicas share nodes: 2 on n1",
+			wantNodes:    2,
+			wantZones:    2,
+			wantReplicas: 3,
+		},
+		{
+			name:         "preferred node anti-affinity not honored",
+			workload:     testWorkload(antiAffinity(false, corev1.LabelHostname), "n1", "n1"),
+			zones:        2,
+			wantRisk:     "HIGH",
+			wantScope:    "preferred/node",
+			wantDetail:   "preferred node anti-affinity not honored: 2 on n1",
+			wantNodes:    1,
+			wantZones:    1,
+			wantReplicas: 2,
+		},
+		{
+			name:         "single zone of several",
+			workload:     testWorkload(antiAffinity(false, corev1.LabelTopologyZone), "n1", "n2"),
+			zones:        2,
+			wantRisk:     "MEDIUM",
+			wantScope:    "preferred/zone",
+			wantDetail:   "preferred zone anti-affinity not honored: all replicas in one zone",
+			wantNodes:    2,
+			wantZones:    1,
+			wantReplicas: 2,
+		},
+		{
+			name:         "single zone cluster",
+			workload:     testWorkload(antiAffinity(true, corev1.LabelHostname), "n1", "n2"),
+			zones:        1,
+			wantRisk:     "LOW",
+			wantScope:    "required/node",
+			wantNodes:    2,
+			wantZones:    1,
+			wantReplicas: 2,
+		},
+		{
+			name:         "no anti-affinity",
+			workload:     testWorkload(corev1.PodSpec{}, "n1", "n3"),
+			zones:        2,
+			wantRisk:     "MEDIUM",
+			wantScope:    "none",
+			wantDetail:   "no anti-affinity or spread constraints, spread only by chance",
+			wantNodes:    2,
+			wantZones:    2,
+			wantReplicas: 2,
+		},
+		{
+			name:         "anti-affinity against another app",
+			workload:     testWorkload(otherApp, "n1", "n3"),
+			zones:        2,
+			wantRisk:     "MEDIUM",
+			wantScope:    "none",
+			wantDetail:   "no anti-affinity or spread constraints, spread only by chance",
+			wantNodes:    2,
+			wantZones:    2,
+			wantReplicas: 2,
+		},
+		{
+			name:         "spread constraints",
+			workload:     testWorkload(spread, "n1", "n3"),
+			zones:        2,
+			wantRisk:     "LOW",
+			wantScope:    "none (spread constraints)",
+			wantNodes:    2,
+			wantZones:    2,
+			wantReplicas: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := colocationRisk(tt.workload, nodes, tt.zones)
+			if got.Risk != tt.wantRisk || got.AntiAffinity != tt.wantScope || got.Detail != tt.wantDetail {
+				t.Errorf("colocationRisk() = %s, %q, %q; want %s, %q, %q", got.Risk, got.AntiAffinity, got.Detail, tt.wantRisk, tt.wantScope, tt.wantDetail)
+			}
+			if got.Nodes != tt.wantNodes || got.Zones != tt.wantZones || got.Replicas != tt.wantReplicas {
+				t.Errorf("colocationRisk() counts %d nodes, %d zones, %d replicas; want %d, %d, %d",
+					got.Nodes, got.Zones, got.Replicas, tt.wantNodes, tt.wantZones, tt.wantReplicas)
+			}
+			if got.Workload != "deployment/web" {
+				t.Errorf("colocationRisk() workload = %q, want deployment/web", got.Workload)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// HelperConfig holds the user preferences read from the config file.
+type HelperConfig struct {
+	// AllNamespacesByDefault searches every namespace when -n is omitted,
+	// instead of the namespace of the current kubeconfig context.
+	AllNamespacesByDefault bool `json:"allNamespacesByDefault"`
+	// Templates are named output layouts selected with -o template:<name>, each
+	// either "go-template=..." or "custom-columns=HEADER:.path,...".
+	Templates map[string]string `json:"templates"`
+	// ServePatterns are the pod name patterns the serve command reports on when
+	// --pattern is omitted.
+	ServePatterns []string `json:"servePatterns"`
+}
+
+// helperConfig is the loaded configuration, populated before any subcommand runs.
+var helperConfig HelperConfig
+
+// configFilePath returns the location of the config file. KUBECTL_HELPER_CONFIG
+// overrides the default of <user config dir>/kubectl-helper/config.yaml.
+func configFilePath() (string, error) {
+	if path := os.Getenv("KUBECTL_HELPER_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(dir, "kubectl-helper", "config.yaml"), nil
+}
+
+// loadConfig reads the config file into helperConfig. A missing file is not an error.
+func loadConfig() error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &helperConfig); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConnectInfo holds the result of one reachability check.
+type ConnectInfo struct {
+	Destination string
+	Method      string
+	Latency     time.Duration
+	Result      string
+	Failed      bool
+}
+
+// connect command flags.
+var (
+	connectPort       int
+	connectHTTPPath   string
+	connectTimeout    time.Duration
+	connectContainer  string
+	connectDebugImage string
+	connectDebug      bool
+)
+
+// connectCmd checks whether a pod can reach other pods or a host:port.
+var connectCmd = &cobra.Command{
+	Use:   "connect [SRC_PATTERN] [DST_PATTERN|HOST:PORT]",
+	Short: "Check TCP/HTTP reachability from the pod matching [SRC_PATTERN] to the pods matching [DST_PATTERN] or to HOST:PORT.",
+	RunE:  runConnectFunc(configFlags),
+	// Checks run inside the source pod and can take a while; keep the output unbuffered.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(connectCmd, "pods")
+	connectCmd.Flags().IntVar(&connectPort, "port", 0,
+		"Destination port for pod destinations. Defaults to the first containerPort of each pod.")
+	connectCmd.Flags().StringVar(&connectHTTPPath, "http", "",
+		"Make an HTTP GET of this path (e.g. /healthz) instead of a plain TCP connect.")
+	connectCmd.Flags().DurationVar(&connectTimeout, "timeout", 5*time.Second,
+		"Timeout of every check.")
+	connectCmd.Flags().StringVarP(&connectContainer, "container", "c", "",
+		"Source container to run the check in. Defaults to the pod's default container.")
+	connectCmd.Flags().StringVar(&connectDebugImage, "debug-image", "busybox:1.36",
+		"Image of the ephemeral debug container injected with --debug-container.")
+	connectCmd.Flags().BoolVar(&connectDebug, "debug-container", false,
+		"Inject an ephemeral debug container when the source container has no nc/curl/wget. It can't be removed again.")
+	addDryRunFlag(connectCmd)
+}
+
+// runConnectFunc returns a function that runs the checks from the source pod.
+func runConnectFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("please provide a source pattern and a destination, for example:\n  kubectl helper connect frontend api\nor:\n  kubectl helper connect -n dev frontend my-svc.dev:8080 --http /healthz")
+		}
+		srcPattern, dst := args[0], args[1]
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		src, err := findSinglePod(ctx, configFlags, clientset, srcPattern)
+		if err != nil {
+			return err
+		}
+		if src.Status.Phase != corev1.PodRunning {
+			return fmt.Errorf("source pod %s is %s, checks can only run in a running pod", src.Name, src.Status.Phase)
+		}
+
+		targets, err := connectTargets(ctx, configFlags, clientset, dst)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", dst)
+			return nil
+		}
+
+		container := connectContainer
+		if container == "" {
+			container = defaultContainer(src)
+		}
+
+		// The exec round trip is measured once and subtracted from every check, so the
+		// latency shown is roughly the one seen from inside the pod.
+		start := time.Now()
+		execInPod(ctx, configFlags, clientset, src, container, []string{"true"})
+		overhead := time.Since(start)
+
+		var results []ConnectInfo
+		// In a dry run the debug container is only reported once.
+		debugSkipped := false
+		for _, target := range targets {
+			result, missing := runConnectCheck(ctx, configFlags, clientset, src, container, target, overhead)
+			if missing && debugSkipped {
+				result.Result = "debug container not added (dry run)"
+				results = append(results, result)
+				continue
+			}
+			if missing && connectDebug {
+				debug, err := addDebugContainer(ctx, clientset, src, container, connectDebugImage, []string{"sleep", "600"}, dryRun)
+				if err != nil {
+					return err
+				}
+				if debug == "" {
+					debugSkipped = true
+					result.Result = "debug container not added (dry run)"
+					results = append(results, result)
+					continue
+				}
+				// Later targets reuse the debug container.
+				container = debug
+				result, missing = runConnectCheck(ctx, configFlags, clientset, src, container, target, overhead)
+			}
+			if missing {
+				result.Failed = true
+				result.Result = "no nc, curl or wget in the container (rerun with --debug-container to inject a debug container)"
+			}
+			results = append(results, result)
+		}
+
+		printConnectTable(src, container, results)
+		return nil
+	}
+}
+
+// connectTargets turns the destination argument into host:port pairs: a literal
+// HOST:PORT, or the IP and port of every pod matching the pattern.
+func connectTargets(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, dst string) ([]string, error) {
+	if host, port, err := net.SplitHostPort(dst); err == nil {
+		if _, err := strconv.Atoi(port); err == nil {
+			return []string{net.JoinHostPort(host, port)}, nil
+		}
+	}
+
+	pods, err := findPods(ctx, configFlags, clientset, dst)
+	if err != nil {
+		return nil, err
+	}
+	var targets []string
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		port := connectPort
+		if port == 0 {
+			for _, c := range pod.Spec.Containers {
+				if len(c.Ports) > 0 {
+					port = int(c.Ports[0].ContainerPort)
+					break
+				}
+			}
+		}
+		if port == 0 {
+			return nil, fmt.Errorf("pod %s declares no containerPort, use --port", pod.Name)
+		}
+		targets = append(targets, net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)))
+	}
+	return targets, nil
+}
+
+// runConnectCheck runs the first available check tool against target. missing is true when
+// the container has none of them.
+func runConnectCheck(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pod *corev1.Pod, container, target string, overhead time.Duration) (ConnectInfo, bool) {
+	info := ConnectInfo{Destination: target}
+	host, port, _ := net.SplitHostPort(target)
+	seconds := strconv.Itoa(max(int(connectTimeout.Seconds()), 1))
+
+	type check struct {
+		method  string
+		command []string
+	}
+	var checks []check
+	if connectHTTPPath != "" {
+		url := fmt.Sprintf("http://%s/%s", target, strings.TrimPrefix(connectHTTPPath, "/"))
+		info.Destination = url
+		checks = []check{
+			{"curl", []string{"curl", "-sS", "-o", "/dev/null", "-w", "%{http_code}", "--max-time", seconds, url}},
+			// wget prints the response headers on stderr with -S.
+			{"wget", []string{"wget", "-q", "-S", "-O", "/dev/null", "-T", seconds, url}},
+		}
+	} else {
+		checks = []check{
+			{"nc", []string{"nc", "-z", "-w", seconds, host, port}},
+			{"bash", []string{"bash", "-c", fmt.Sprintf("exec 3<>/dev/tcp/%s/%s", host, port)}},
+		}
+	}
+
+	for _, c := range checks {
+		start := time.Now()
+		stdout, stderr, err := execInPod(ctx, configFlags, clientset, pod, container, c.command)
+		if err != nil && commandMissing(err, stderr) {
+			continue
+		}
+		info.Method = c.method
+		info.Latency = max(time.Since(start)-overhead, 0)
+		switch {
+		case err != nil:
+			info.Failed = true
+			info.Result = "failed: " + firstLine(stderr+stdout, err.Error())
+		case c.method == "curl":
+			info.Result = "HTTP " + strings.TrimSpace(stdout)
+			info.Failed = !strings.HasPrefix(info.Result, "HTTP 2") && !strings.HasPrefix(info.Result, "HTTP 3")
+		case c.method == "wget":
+			info.Result = firstLine(stderr, "HTTP response received")
+		default:
+			info.Result = "connected"
+		}
+		return info, false
+	}
+	return info, true
+}
+
+// printConnectTable prints the checks made from container of src, failures in red.
+func printConnectTable(src *corev1.Pod, container string, results []ConnectInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	okColor := color.New(color.FgGreen)
+	errorColor := color.New(color.FgRed)
+
+	fmt.Println()
+	headerColor.Printf("From %s/%s, container %s (%s)\n", src.Namespace, src.Name, container, src.Status.PodIP)
+	headerColor.Printf("%-50s %-15s %-10s %-60s\n", "DESTINATION", "METHOD", "LATENCY", "RESULT")
+	line := strings.Repeat("-", 135)
+	lineColor.Println(line)
+	for _, r := range results {
+		latency := "-"
+		if r.Method != "" {
+			latency = r.Latency.Round(time.Millisecond).String()
+		}
+		fmt.Printf("%-50s %-15s %-10s ", r.Destination, r.Method, latency)
+		if r.Failed {
+			errorColor.Println(r.Result)
+		} else {
+			okColor.Println(r.Result)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ContainerInfo holds the state of one container of a matched pod.
+type ContainerInfo struct {
+	Pod       string
+	Namespace string
+	Name      string
+	Init      bool
+	Image     string
+	State     string
+	Ready     bool
+	Restarts  int32
+	Reason    string
+}
+
+// containersInit includes the init containers and their progress.
+var containersInit bool
+
+// containersCmd lists the containers of the matching pods.
+var containersCmd = &cobra.Command{
+	Use:   "containers [SEARCH_PATTERN]",
+	Short: "List the containers of pods containing [SEARCH_PATTERN] with their image, state and restarts.",
+	RunE:  runContainersFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(containersCmd, "pods")
+	containersCmd.Flags().BoolVar(&containersInit, "init", false,
+		"Include init containers and show which one a pending pod is blocked on.")
+}
+
+// runContainersFunc returns a function that prints one row per container of the matching pods.
+func runContainersFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper containers nginx\nor:\n  kubectl helper containers -n dev --init nginx")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var rows []ContainerInfo
+		var blocked []string
+		for i := range pods {
+			pod := &pods[i]
+			if containersInit {
+				rows = append(rows, containerInfos(pod, pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true)...)
+				if done, total, blocking := initContainerProgress(pod); blocking != "" {
+					blocked = append(blocked, fmt.Sprintf("%s/%s: %d/%d init containers done, %s", pod.Namespace, pod.Name, done, total, blocking))
+				}
+			}
+			rows = append(rows, containerInfos(pod, pod.Spec.Containers, pod.Status.ContainerStatuses, false)...)
+		}
+
+		printContainerTable(rows)
+		if len(blocked) > 0 {
+			warnColor := color.New(color.FgYellow, color.Bold)
+			for _, b := range blocked {
+				warnColor.Println(b)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+}
+
+// containerInfos pairs every container spec with its status.
+func containerInfos(pod *corev1.Pod, containers []corev1.Container, statuses []corev1.ContainerStatus, init bool) []ContainerInfo {
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, st := range statuses {
+		byName[st.Name] = st
+	}
+
+	rows := make([]ContainerInfo, 0, len(containers))
+	for _, c := range containers {
+		row := ContainerInfo{
+			Pod:       pod.Name,
+			Namespace: pod.Namespace,
+			Name:      c.Name,
+			Init:      init,
+			Image:     c.Image,
+			State:     "Unknown",
+		}
+		if st, ok := byName[c.Name]; ok {
+			row.Ready = st.Ready
+			row.Restarts = st.RestartCount
+			row.State, row.Reason = containerState(st.State)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// containerState returns the state name and, for waiting or terminated containers, the reason.
+func containerState(state corev1.ContainerState) (string, string) {
+	switch {
+	case state.Running != nil:
+		return "Running", ""
+	case state.Waiting != nil:
+		return "Waiting", state.Waiting.Reason
+	case state.Terminated != nil:
+		reason := state.Terminated.Reason
+		if state.Terminated.ExitCode != 0 {
+			reason = fmt.Sprintf("%s (exit %d)", reason, state.Terminated.ExitCode)
+		}
+		return "Terminated", reason
+	}
+	return "Unknown", ""
+}
+
+// initContainerProgress returns how many init containers of pod have completed, how many
+// there are, and, if the pod is still initializing, a description of the init container it
+// is blocked on. Sidecar init containers (restartPolicy: Always) count as done once started.
+func initContainerProgress(pod *corev1.Pod) (done, total int, blocking string) {
+	total = len(pod.Spec.InitContainers)
+	statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.InitContainerStatuses))
+	for _, st := range pod.Status.InitContainerStatuses {
+		statuses[st.Name] = st
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		st, ok := statuses[c.Name]
+		sidecar := c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		switch {
+		case ok && sidecar && st.Started != nil && *st.Started:
+			done++
+			continue
+		case ok && st.State.Terminated != nil && st.State.Terminated.ExitCode == 0:
+			done++
+			continue
+		}
+
+		// Init containers run in order, so the first unfinished one is what the pod waits on.
+		if blocking == "" && pod.Status.Phase == corev1.PodPending {
+			state, reason := "not started", ""
+			if ok {
+				state, reason = containerState(st.State)
+				state = strings.ToLower(state)
+			}
+			blocking = fmt.Sprintf("blocked on %s (%s", c.Name, state)
+			if reason != "" {
+				blocking += ": " + reason
+			}
+			blocking += ")"
+		}
+	}
+	return done, total, blocking
+}
+
+// initColumn renders the INIT column of the wide ip output, e.g. "2/3 blocked on migrate (waiting: CrashLoopBackOff)".
+func initColumn(pod *corev1.Pod) string {
+	done, total, blocking := initContainerProgress(pod)
+	if total == 0 {
+		return "-"
+	}
+	if blocking != "" {
+		return fmt.Sprintf("%d/%d %s", done, total, blocking)
+	}
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
+// printContainerTable prints one row per container, coloring states that need attention.
+func printContainerTable(rows []ContainerInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	waitingColor := color.New(color.FgYellow, color.Bold)
+	failedColor := color.New(color.FgRed, color.Bold)
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-25s %-5s %-45s %-11s %-6s %-9s %-30s\n",
+		"POD", "NAMESPACE", "CONTAINER", "INIT", "IMAGE", "STATE", "READY", "RESTARTS", "REASON")
+
+	line := strings.Repeat("-", 190)
+	lineColor.Println(line)
+
+	for _, r := range rows {
+		initMark := ""
+		if r.Init {
+			initMark = "yes"
+		}
+		fmt.Printf("%-35s %-15s %-25s %-5s %-45s ", r.Pod, r.Namespace, r.Name, initMark, r.Image)
+		state := fmt.Sprintf("%-11s", r.State)
+		switch {
+		case r.State == "Waiting":
+			waitingColor.Print(state)
+		case r.State == "Terminated" && strings.Contains(r.Reason, "exit"):
+			failedColor.Print(state)
+		default:
+			fmt.Print(state)
+		}
+		fmt.Printf(" %-6t %-9d %-30s\n", r.Ready, r.Restarts, r.Reason)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// noDaemonFlag makes commands list the cluster even when a daemon is running.
+var noDaemonFlag bool
+
+// daemonCmd keeps pods, services and nodes in an informer cache and answers the other
+// commands from it over a unix socket.
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep pods, services and nodes of the current context cached and let the other commands query the cache.",
+	RunE:  runDaemonFunc(configFlags),
+	// Logs until interrupted, like serve.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&noDaemonFlag, "no-daemon", false,
+		"Always query the API server, even when kubectl helper daemon is running.")
+}
+
+// runDaemonFunc returns a function that syncs the informers and serves the cache until interrupted.
+func runDaemonFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		color.New(color.FgCyan).Fprintln(os.Stderr, "Syncing pods, services and nodes...")
+		server, err := startCacheServer(ctx, configFlags)
+		if err != nil {
+			return err
+		}
+		color.New(color.FgGreen).Fprintf(os.Stderr, "Serving the cache on %s (Ctrl+C to stop)\n", server.path)
+		return server.serve()
+	}
+}
+
+// cacheServer serves the informer caches of pods, services and nodes on a unix socket.
+type cacheServer struct {
+	path     string
+	listener net.Listener
+	server   *http.Server
+}
+
+// startCacheServer syncs the informers and listens on the socket of the current context.
+// The caches stop, and serve returns, when ctx is done.
+func startCacheServer(ctx context.Context, configFlags *genericclioptions.ConfigFlags) (*cacheServer, error) {
+	// Clients with these flags never ask the daemon, so its cache would go unused.
+	if connectionOverridden(configFlags) {
+		return nil, fmt.Errorf("the daemon serves kubeconfig contexts as they are; use --context or --kubeconfig instead of --server, --user, --as, --token and the like")
+	}
+	path, err := daemonSocketPath(configFlags)
+	if err != nil {
+		return nil, err
+	}
+	if daemonRunning(path) {
+		return nil, fmt.Errorf("a daemon is already running for this context on %s", path)
+	}
+	clientset, err := newClientset(configFlags)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCacheAccess(ctx, clientset); err != nil {
+		return nil, err
+	}
+
+	factory := informers.NewSharedInformerFactory(clientset, 10*time.Minute)
+	pods := factory.Core().V1().Pods()
+	services := factory.Core().V1().Services()
+	nodes := factory.Core().V1().Nodes()
+	var synced []cache.InformerSynced
+	for _, informer := range []cache.SharedIndexInformer{pods.Informer(), services.Informer(), nodes.Informer()} {
+		// managedFields are never shown and take a good part of the memory.
+		if err := informer.SetTransform(stripManagedFields); err != nil {
+			return nil, err
+		}
+		synced = append(synced, informer.HasSynced)
+	}
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+		return nil, fmt.Errorf("failed to sync the cache")
+	}
+
+	// A socket file left behind by a daemon that died is in the way of Listen.
+	os.Remove(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// The cache holds whatever the daemon's credentials can see; keep it to this user.
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/pods", func(w http.ResponseWriter, r *http.Request) {
+		var list []*corev1.Pod
+		var err error
+		if ns := r.URL.Query().Get("namespace"); ns == metav1.NamespaceAll {
+			list, err = pods.Lister().List(labels.Everything())
+		} else {
+			list, err = pods.Lister().Pods(ns).List(labels.Everything())
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, list)
+	})
+	mux.HandleFunc("/v1/services", func(w http.ResponseWriter, r *http.Request) {
+		var list []*corev1.Service
+		var err error
+		if ns := r.URL.Query().Get("namespace"); ns == metav1.NamespaceAll {
+			list, err = services.Lister().List(labels.Everything())
+		} else {
+			list, err = services.Lister().Services(ns).List(labels.Everything())
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, list)
+	})
+	mux.HandleFunc("/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		list, err := nodes.Lister().List(labels.Everything())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, list)
+	})
+
+	s := &cacheServer{
+		path:     path,
+		listener: listener,
+		server:   &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+	}
+	go func() {
+		<-ctx.Done()
+		s.server.Close()
+	}()
+	return s, nil
+}
+
+// serve answers queries until the context of startCacheServer is done, then removes the socket.
+func (s *cacheServer) serve() error {
+	defer os.Remove(s.path)
+	if err := s.server.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("failed to serve on %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// checkCacheAccess makes sure the informers can list and watch pods, services and nodes
+// cluster-wide. Without the permission they would retry forever and never sync.
+func checkCacheAccess(ctx context.Context, clientset kubernetes.Interface) error {
+	for _, resource := range []string{"pods", "services", "nodes"} {
+		for _, verb := range []string{"list", "watch"} {
+			review := &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{Verb: verb, Resource: resource},
+				},
+			}
+			result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to check access to %s: %w", resource, err)
+			}
+			if !result.Status.Allowed {
+				return fmt.Errorf("the cache needs to %s %s in all namespaces, which you are not allowed to", verb, resource)
+			}
+		}
+	}
+	return nil
+}
+
+// stripManagedFields is an informer transform dropping the managedFields of objects.
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
+
+// daemonRunning reports whether a daemon answers on the socket at path.
+func daemonRunning(path string) bool {
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// daemonSocketPath returns the socket of the daemon for the current connection, next to
+// the config file. Context names like "default" repeat across kubeconfigs, so the API
+// server, the kubeconfig files and the user are hashed in too; hashing also keeps
+// EKS-style names from overflowing a socket path.
+func daemonSocketPath(configFlags *genericclioptions.ConfigFlags) (string, error) {
+	loader := configFlags.ToRawKubeConfigLoader()
+	raw, err := loader.RawConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	contextName := raw.CurrentContext
+	if configFlags.Context != nil && *configFlags.Context != "" {
+		contextName = *configFlags.Context
+	}
+	authInfo := ""
+	if kubeContext, ok := raw.Contexts[contextName]; ok {
+		authInfo = kubeContext.AuthInfo
+	}
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	configPath, err := configFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	h := fnv.New32a()
+	for _, part := range append([]string{contextName, restConfig.Host, authInfo, restConfig.Username},
+		loader.ConfigAccess().GetLoadingPrecedence()...) {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return filepath.Join(filepath.Dir(configPath), fmt.Sprintf("daemon-%08x.sock", h.Sum32())), nil
+}
+
+// connectionOverridden reports whether flags change the server or identity of the
+// kubeconfig context, so a daemon's cache may belong to another cluster or user.
+func connectionOverridden(configFlags *genericclioptions.ConfigFlags) bool {
+	for _, s := range []*string{
+		configFlags.APIServer, configFlags.ClusterName, configFlags.AuthInfoName,
+		configFlags.Impersonate, configFlags.ImpersonateUID, configFlags.BearerToken,
+		configFlags.Username, configFlags.Password, configFlags.CertFile, configFlags.KeyFile,
+		configFlags.CAFile, configFlags.TLSServerName,
+	} {
+		if s != nil && *s != "" {
+			return true
+		}
+	}
+	if configFlags.ImpersonateGroup != nil && len(*configFlags.ImpersonateGroup) > 0 {
+		return true
+	}
+	return configFlags.Insecure != nil && *configFlags.Insecure
+}
+
+// daemonList fetches a resource list of namespace ("" for all) from a running daemon. ok is
+// false when no daemon answers, or when the cache doesn't fit the request, and the caller
+// lists the cluster itself.
+func daemonList[T any](configFlags *genericclioptions.ConfigFlags, resource, namespace string) (items []T, ok bool) {
+	if noDaemonFlag {
+		return nil, false
+	}
+	// The cache holds what the daemon's own identity can see on its own cluster.
+	if connectionOverridden(configFlags) {
+		return nil, false
+	}
+	path, err := daemonSocketPath(configFlags)
+	if err != nil {
+		return nil, false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, false
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		},
+	}
+	resp, err := client.Get("http://daemon/v1/" + resource + "?namespace=" + namespace)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, false
+	}
+	return items, true
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// verbosity is the klog level client-go logs at; 6 and up log every request it makes.
+var verbosity int
+
+// debugFlag prints every API request with its status and duration on stderr.
+var debugFlag bool
+
+func init() {
+	RootCmd.PersistentFlags().IntVarP(&verbosity, "verbose", "v", 0,
+		"Log level of the Kubernetes client, like kubectl's -v. 6 logs the requests, 8 their bodies.")
+	RootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false,
+		"Print every API request made, with its status and duration, on stderr.")
+}
+
+// setupLogging applies -v to klog and hooks --debug into the clients built from configFlags.
+func setupLogging() error {
+	if verbosity < 0 {
+		return fmt.Errorf("-v/--verbose can't be negative")
+	}
+	klogFlags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(klogFlags)
+	if err := klogFlags.Set("v", strconv.Itoa(verbosity)); err != nil {
+		return err
+	}
+
+	if debugFlag {
+		restConfigWrappers = append(restConfigWrappers, func(c *rest.Config) {
+			c.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+				return &debugRoundTripper{next: rt}
+			})
+		})
+	}
+	return nil
+}
+
+// debugRoundTripper logs the requests passing through it.
+type debugRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (d *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	debugColor := color.New(color.FgHiBlack)
+	start := time.Now()
+	resp, err := d.next.RoundTrip(req)
+	took := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		debugColor.Fprintf(os.Stderr, "[debug] %s %s failed after %s: %v\n", req.Method, req.URL.RequestURI(), took, err)
+		return resp, err
+	}
+	debugColor.Fprintf(os.Stderr, "[debug] %s %s %s in %s\n", req.Method, req.URL.RequestURI(), resp.Status, took)
+	return resp, nil
+}
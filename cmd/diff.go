@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// SpecDiff holds one field that differs between the two pods.
+type SpecDiff struct {
+	Field string
+	A     string
+	B     string
+}
+
+// diffCmd compares the specs of two pods.
+var diffCmd = &cobra.Command{
+	Use:   "diff [POD_A_PATTERN] [POD_B_PATTERN]",
+	Short: "Show a normalized diff of the specs (images, env, resources, volumes, ...) of two pods.",
+	RunE:  runDiffFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(diffCmd, "pods")
+}
+
+// runDiffFunc returns a function that resolves both patterns to a single pod and prints
+// every spec field whose value differs between them.
+func runDiffFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("please provide two search patterns, for example:\n  kubectl helper diff web-7d9f web-canary\nor:\n  kubectl helper diff -n dev api-0 api-1")
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		podA, err := findSinglePod(ctx, configFlags, clientset, args[0])
+		if err != nil {
+			return err
+		}
+		podB, err := findSinglePod(ctx, configFlags, clientset, args[1])
+		if err != nil {
+			return err
+		}
+
+		fieldsA, err := normalizedSpecFields(podA)
+		if err != nil {
+			return err
+		}
+		fieldsB, err := normalizedSpecFields(podB)
+		if err != nil {
+			return err
+		}
+
+		printSpecDiff(podA, podB, diffSpecFields(fieldsA, fieldsB))
+		return nil
+	}
+}
+
+// normalizedSpecFields flattens the pod spec into "path -> value" pairs. Fields the API
+// server or kubelet set per pod (node, hostname, the generated service account token
+// volume) are dropped, so only differences in the declared spec remain. List items that
+// carry a name (containers, env, volumes, ...) are keyed by that name instead of their
+// index, so reordering them doesn't show up as a difference.
+func normalizedSpecFields(pod *corev1.Pod) (map[string]string, error) {
+	spec := pod.Spec.DeepCopy()
+	spec.NodeName = ""
+	spec.Hostname = ""
+
+	generated := map[string]bool{}
+	var volumes []corev1.Volume
+	for _, vol := range spec.Volumes {
+		if strings.HasPrefix(vol.Name, "kube-api-access-") {
+			generated[vol.Name] = true
+			continue
+		}
+		volumes = append(volumes, vol)
+	}
+	spec.Volumes = volumes
+
+	stripMounts := func(containers []corev1.Container) {
+		for i := range containers {
+			var mounts []corev1.VolumeMount
+			for _, m := range containers[i].VolumeMounts {
+				if !generated[m.Name] {
+					mounts = append(mounts, m)
+				}
+			}
+			containers[i].VolumeMounts = mounts
+		}
+	}
+	stripMounts(spec.InitContainers)
+	stripMounts(spec.Containers)
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec of pod %s: %w", pod.Name, err)
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to decode spec of pod %s: %w", pod.Name, err)
+	}
+
+	fields := map[string]string{}
+	flattenFields("", obj, fields)
+	return fields, nil
+}
+
+// flattenFields walks v and records every leaf value under its dotted path in out.
+func flattenFields(path string, v interface{}, out map[string]string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			flattenFields(child, value, out)
+		}
+	case []interface{}:
+		for i, item := range t {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			if m, ok := item.(map[string]interface{}); ok {
+				if name, ok := m["name"].(string); ok && name != "" {
+					child = fmt.Sprintf("%s[%s]", path, name)
+				}
+			}
+			flattenFields(child, item, out)
+		}
+	default:
+		out[path] = fmt.Sprint(t)
+	}
+}
+
+// diffSpecFields returns the fields whose values differ, ordered by field path.
+func diffSpecFields(a, b map[string]string) []SpecDiff {
+	keys := map[string]bool{}
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	var diffs []SpecDiff
+	for k := range keys {
+		valueA, okA := a[k]
+		valueB, okB := b[k]
+		if okA && okB && valueA == valueB {
+			continue
+		}
+		if !okA {
+			valueA = "<unset>"
+		}
+		if !okB {
+			valueB = "<unset>"
+		}
+		diffs = append(diffs, SpecDiff{Field: k, A: valueA, B: valueB})
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Field < diffs[j].Field })
+	return diffs
+}
+
+// printSpecDiff prints the differing fields side by side.
+func printSpecDiff(podA, podB *corev1.Pod, diffs []SpecDiff) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	aColor := color.New(color.FgRed)
+	bColor := color.New(color.FgGreen)
+
+	fmt.Println()
+	headerColor.Printf("A: %s/%s\n", podA.Namespace, podA.Name)
+	headerColor.Printf("B: %s/%s\n", podB.Namespace, podB.Name)
+	fmt.Println()
+
+	if len(diffs) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("The pod specs are identical.")
+		fmt.Println()
+		return
+	}
+
+	headerColor.Printf("%-60s %-40s %-40s\n", "FIELD", "A", "B")
+	line := strings.Repeat("-", 140)
+	lineColor.Println(line)
+
+	for _, d := range diffs {
+		fmt.Printf("%-60s ", d.Field)
+		aColor.Printf("%-40s ", d.A)
+		bColor.Printf("%-40s\n", d.B)
+	}
+	fmt.Println()
+	fmt.Printf("%d field(s) differ\n\n", len(diffs))
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DNSInfo holds the result of one DNS lookup made inside a pod.
+type DNSInfo struct {
+	Pod        string
+	Namespace  string
+	Method     string
+	Took       time.Duration
+	Result     string
+	Failed     bool
+	ResolvConf string
+}
+
+// dns command flags.
+var (
+	dnsContainer  string
+	dnsDebugImage string
+	dnsDebug      bool
+)
+
+// dnsCmd resolves a hostname from inside the matching pods.
+var dnsCmd = &cobra.Command{
+	Use:   "dns [SEARCH_PATTERN] [HOSTNAME]",
+	Short: "Resolve [HOSTNAME] (default kubernetes.default) inside pods containing [SEARCH_PATTERN] and show their resolv.conf.",
+	RunE:  runDNSFunc(configFlags),
+	// Lookups run inside the pods and can take a while; keep the output unbuffered.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(dnsCmd, "pods")
+	dnsCmd.Flags().StringVarP(&dnsContainer, "container", "c", "",
+		"Container to run the lookup in. Defaults to the pod's default container.")
+	dnsCmd.Flags().StringVar(&dnsDebugImage, "debug-image", "busybox:1.36",
+		"Image of the ephemeral debug container injected with --debug-container.")
+	dnsCmd.Flags().BoolVar(&dnsDebug, "debug-container", false,
+		"Inject an ephemeral debug container into pods whose container has no getent or nslookup. It can't be removed again.")
+	addDryRunFlag(dnsCmd)
+}
+
+// runDNSFunc returns a function that runs the lookup in every running matching pod.
+func runDNSFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper dns api\nor:\n  kubectl helper dns -n dev api my-service.other-ns")
+		}
+		searchTerm := args[0]
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+		hostname := "kubernetes.default"
+		if len(args) > 1 {
+			hostname = args[1]
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var results []DNSInfo
+		for i := range pods {
+			pod := &pods[i]
+			if pod.Status.Phase != corev1.PodRunning {
+				results = append(results, DNSInfo{Pod: pod.Name, Namespace: pod.Namespace, Method: "-",
+					Result: fmt.Sprintf("skipped, pod is %s", pod.Status.Phase), Failed: true})
+				continue
+			}
+			results = append(results, lookupInPod(ctx, configFlags, clientset, pod, hostname, dryRun))
+		}
+
+		printDNSTable(hostname, results)
+		printResolvConfs(hostname, results)
+		return nil
+	}
+}
+
+// lookupInPod resolves hostname with getent, falling back to nslookup and finally to an
+// ephemeral debug container with --debug-container, which shares the pod's network namespace and resolv.conf.
+func lookupInPod(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pod *corev1.Pod, hostname string, dryRun dryRunStrategy) DNSInfo {
+	info := DNSInfo{Pod: pod.Name, Namespace: pod.Namespace}
+	container := dnsContainer
+	if container == "" {
+		container = defaultContainer(pod)
+	}
+
+	lookups := []struct {
+		method  string
+		command []string
+	}{
+		{"getent", []string{"getent", "hosts", hostname}},
+		{"nslookup", []string{"nslookup", hostname}},
+	}
+
+	try := func(container string) bool {
+		for _, l := range lookups {
+			start := time.Now()
+			stdout, stderr, err := execInPod(ctx, configFlags, clientset, pod, container, l.command)
+			if err != nil && commandMissing(err, stderr) {
+				continue
+			}
+			info.Method = l.method
+			info.Took = time.Since(start)
+			addresses := parseLookupAddresses(l.method, stdout)
+			switch {
+			case len(addresses) > 0:
+				info.Result = strings.Join(addresses, ", ")
+			case err != nil:
+				info.Failed = true
+				info.Result = "failed: " + firstLine(stderr+stdout, err.Error())
+			default:
+				info.Failed = true
+				info.Result = "no addresses returned"
+			}
+			resolv, _, _ := execInPod(ctx, configFlags, clientset, pod, container, []string{"cat", "/etc/resolv.conf"})
+			info.ResolvConf = resolv
+			return true
+		}
+		return false
+	}
+
+	if try(container) {
+		return info
+	}
+	if !dnsDebug {
+		info.Method = "-"
+		info.Failed = true
+		info.Result = fmt.Sprintf("container %s has neither getent nor nslookup (rerun with --debug-container to inject a debug container)", container)
+		return info
+	}
+
+	debug, err := addDebugContainer(ctx, clientset, pod, container, dnsDebugImage, []string{"sleep", "600"}, dryRun)
+	if err != nil {
+		info.Method = "-"
+		info.Failed = true
+		info.Result = err.Error()
+		return info
+	}
+	if debug == "" {
+		info.Method = "-"
+		info.Result = "debug container not added (dry run)"
+		return info
+	}
+	if !try(debug) {
+		info.Method = "-"
+		info.Failed = true
+		info.Result = fmt.Sprintf("debug image %s has neither getent nor nslookup", dnsDebugImage)
+		return info
+	}
+	info.Method += " (debug)"
+	return info
+}
+
+// parseLookupAddresses extracts the resolved addresses from getent or nslookup output.
+func parseLookupAddresses(method, output string) []string {
+	var addresses []string
+	// nslookup prints the server it asked first; answers follow the "Name:" line.
+	inAnswer := method == "getent"
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch method {
+		case "getent":
+			addresses = append(addresses, fields[0])
+		case "nslookup":
+			if strings.HasPrefix(line, "Name:") {
+				inAnswer = true
+				continue
+			}
+			if inAnswer && strings.HasPrefix(line, "Address") {
+				addresses = append(addresses, fields[len(fields)-1])
+			}
+		}
+	}
+	return addresses
+}
+
+// firstLine returns the first non-empty line of s, or fallback.
+func firstLine(s, fallback string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return fallback
+}
+
+// resolvConfSummary condenses a resolv.conf to its nameservers, search domains and ndots.
+func resolvConfSummary(resolv string) (nameservers, search []string, ndots int) {
+	// The resolver default when options doesn't set it.
+	ndots = 1
+	for _, line := range strings.Split(resolv, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			nameservers = append(nameservers, fields[1])
+		case "search":
+			search = fields[1:]
+		case "options":
+			for _, opt := range fields[1:] {
+				if v, ok := strings.CutPrefix(opt, "ndots:"); ok {
+					fmt.Sscanf(v, "%d", &ndots)
+				}
+			}
+		}
+	}
+	return nameservers, search, ndots
+}
+
+// printDNSTable prints the lookup result of every pod, failures in red.
+func printDNSTable(hostname string, results []DNSInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	errorColor := color.New(color.FgRed)
+
+	fmt.Println()
+	headerColor.Printf("Resolving %s\n", hostname)
+	headerColor.Printf("%-35s %-15s %-20s %-10s %-60s\n", "POD", "NAMESPACE", "METHOD", "TIME", "RESULT")
+	line := strings.Repeat("-", 145)
+	lineColor.Println(line)
+	for _, r := range results {
+		took := "-"
+		if r.Took > 0 {
+			took = r.Took.Round(time.Millisecond).String()
+		}
+		fmt.Printf("%-35s %-15s %-20s %-10s ", r.Pod, r.Namespace, r.Method, took)
+		if r.Failed {
+			errorColor.Println(r.Result)
+		} else {
+			fmt.Println(r.Result)
+		}
+	}
+	fmt.Println()
+}
+
+// printResolvConfs prints each distinct resolv.conf once with the pods using it, and warns
+// when ndots makes the resolver try every search domain before the name itself.
+func printResolvConfs(hostname string, results []DNSInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	warnColor := color.New(color.FgYellow)
+
+	podsByConf := map[string][]string{}
+	var confs []string
+	for _, r := range results {
+		if r.ResolvConf == "" {
+			continue
+		}
+		if _, ok := podsByConf[r.ResolvConf]; !ok {
+			confs = append(confs, r.ResolvConf)
+		}
+		podsByConf[r.ResolvConf] = append(podsByConf[r.ResolvConf], r.Namespace+"/"+r.Pod)
+	}
+	sort.Strings(confs)
+
+	for _, conf := range confs {
+		nameservers, search, ndots := resolvConfSummary(conf)
+		headerColor.Printf("resolv.conf of %s\n", strings.Join(podsByConf[conf], ", "))
+		fmt.Printf("  nameserver: %s\n", strings.Join(nameservers, ", "))
+		fmt.Printf("  search:     %s\n", strings.Join(search, " "))
+		fmt.Printf("  ndots:      %d\n", ndots)
+		if dots := strings.Count(hostname, "."); !strings.HasSuffix(hostname, ".") && dots < ndots && len(search) > 0 {
+			warnColor.Printf("  ! %s has %d dot(s) < ndots:%d, so up to %d search domain lookups happen before it is tried as is; use a trailing dot for external names\n",
+				hostname, dots, ndots, len(search))
+		}
+		fmt.Println()
+	}
+}
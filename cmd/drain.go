@@ -0,0 +1,361 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EvictionInfo is a pod that drain evicts, with the PodDisruptionBudget guarding it.
+type EvictionInfo struct {
+	Pod *corev1.Pod
+	// Owner is the top-level workload of the pod as "Kind/Name".
+	Owner string
+	// PDB is the name of the matching budget, empty when there is none.
+	PDB string
+	// DisruptionsAllowed is the number of pods the budget lets go right now.
+	DisruptionsAllowed int32
+	// Refused says why, like kubectl drain, the pod isn't evicted without --force or
+	// --delete-emptydir-data; empty when it is.
+	Refused string
+}
+
+// drain command flags.
+var (
+	drainYes                bool
+	drainTimeout            time.Duration
+	drainForce              bool
+	drainDeleteEmptyDirData bool
+)
+
+// drainCmd cordons the nodes matching a pattern and evicts their pods.
+var drainCmd = &cobra.Command{
+	Use:   "drain [NODE_PATTERN]",
+	Short: "Cordon nodes containing [NODE_PATTERN] and evict their pods, respecting PodDisruptionBudgets.",
+	RunE:  runDrainFunc(configFlags),
+	// The pod table has to be on screen before the confirmation prompt.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+// uncordonCmd makes the nodes matching a pattern schedulable again.
+var uncordonCmd = &cobra.Command{
+	Use:   "uncordon [NODE_PATTERN]",
+	Short: "Mark nodes containing [NODE_PATTERN] schedulable again.",
+	RunE:  runUncordonFunc(configFlags),
+}
+
+func init() {
+	addDryRunFlag(drainCmd)
+	drainCmd.Flags().BoolVarP(&drainYes, "yes", "y", false, "Drain without asking for confirmation.")
+	drainCmd.Flags().DurationVar(&drainTimeout, "timeout", 5*time.Minute,
+		"How long to keep retrying evictions a PodDisruptionBudget refuses.")
+	drainCmd.Flags().BoolVar(&drainForce, "force", false,
+		"Also evict pods without a controller. Nothing recreates them, they are gone for good.")
+	drainCmd.Flags().BoolVar(&drainDeleteEmptyDirData, "delete-emptydir-data", false,
+		"Also evict pods using emptyDir volumes, whose data is deleted with the pod.")
+	addDryRunFlag(uncordonCmd)
+}
+
+// runDrainFunc returns a function that cordons the matching nodes and evicts their pods after confirmation.
+func runDrainFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a node pattern, for example:\n  kubectl helper drain worker-3\nor:\n  kubectl helper drain pool-a --dry-run=server")
+		}
+		searchTerm := args[0]
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		nodes, err := findNodes(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		historyMatches = len(nodes)
+		if len(nodes) == 0 {
+			fmt.Printf("No nodes found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		resolver, err := newOwnerResolver(configFlags)
+		if err != nil {
+			return err
+		}
+		evictions, err := podsToEvict(ctx, clientset, resolver, nodes)
+		if err != nil {
+			return err
+		}
+		printEvictionTable(nodes, evictions)
+		var refused int
+		for _, e := range evictions {
+			if e.Refused != "" {
+				refused++
+			}
+		}
+		if refused > 0 {
+			return fmt.Errorf("%d pod(s) can't be evicted safely, nothing was changed; rerun with --force and/or --delete-emptydir-data to evict them anyway", refused)
+		}
+
+		if dryRun == dryRunNone && !drainYes {
+			ok, err := confirm(fmt.Sprintf("Cordon %d node(s) and evict %d pod(s)?", len(nodes), len(evictions)))
+			if err != nil || !ok {
+				return err
+			}
+		}
+		for _, node := range nodes {
+			if err := setUnschedulable(ctx, clientset, node, true, dryRun); err != nil {
+				return err
+			}
+		}
+		// A pod whose budget keeps refusing it until --timeout doesn't stop the others.
+		var failed []string
+		for _, e := range evictions {
+			if err := evictPod(ctx, clientset, e.Pod, dryRun); err != nil {
+				color.New(color.FgRed).Printf("%v\n", err)
+				failed = append(failed, e.Pod.Namespace+"/"+e.Pod.Name)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to evict %d pod(s): %s", len(failed), strings.Join(failed, ", "))
+		}
+		return nil
+	}
+}
+
+// runUncordonFunc returns a function that marks the matching nodes schedulable.
+func runUncordonFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a node pattern, for example:\n  kubectl helper uncordon worker-3")
+		}
+		searchTerm := args[0]
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		nodes, err := findNodes(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		historyMatches = len(nodes)
+		if len(nodes) == 0 {
+			fmt.Printf("No nodes found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+		for _, node := range nodes {
+			if err := setUnschedulable(cmd.Context(), clientset, node, false, dryRun); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// findNodes returns the names of the nodes matching the pattern, sorted.
+func findNodes(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pattern string) ([]string, error) {
+	items, ok := daemonList[corev1.Node](configFlags, "nodes", "")
+	if !ok {
+		list, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+		items = list.Items
+	}
+	var nodes []string
+	for _, node := range items {
+		if matchesPattern(node.Name, pattern) {
+			nodes = append(nodes, node.Name)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+// podsToEvict returns the pods on the nodes that a drain evicts, like kubectl drain
+// --ignore-daemonsets: DaemonSet and mirror pods stay, finished pods are skipped. Pods
+// without a controller or with emptyDir volumes are marked Refused unless --force or
+// --delete-emptydir-data allow them.
+func podsToEvict(ctx context.Context, clientset kubernetes.Interface, resolver *ownerResolver, nodes []string) ([]EvictionInfo, error) {
+	var pods []corev1.Pod
+	for _, node := range nodes {
+		list, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + node})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+		}
+		pods = append(pods, list.Items...)
+	}
+
+	pdbs := map[string][]policyv1.PodDisruptionBudget{}
+	var evictions []EvictionInfo
+	for i := range pods {
+		pod := &pods[i]
+		if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "DaemonSet" {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		budgets, ok := pdbs[pod.Namespace]
+		if !ok {
+			list, err := clientset.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve poddisruptionbudgets: %w", err)
+			}
+			budgets = list.Items
+			pdbs[pod.Namespace] = budgets
+		}
+		kind, name := resolver.podWorkload(ctx, pod)
+		e := EvictionInfo{Pod: pod, Owner: kind + "/" + name}
+		switch {
+		case metav1.GetControllerOf(pod) == nil && !drainForce:
+			e.Refused = "no controller recreates it (--force)"
+		case usesEmptyDir(pod) && !drainDeleteEmptyDirData:
+			e.Refused = "emptyDir data is deleted (--delete-emptydir-data)"
+		}
+		for _, pdb := range budgets {
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			e.PDB = pdb.Name
+			e.DisruptionsAllowed = pdb.Status.DisruptionsAllowed
+			break
+		}
+		evictions = append(evictions, e)
+	}
+
+	sort.Slice(evictions, func(i, j int) bool {
+		a, b := evictions[i].Pod, evictions[j].Pod
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return evictions, nil
+}
+
+// usesEmptyDir reports whether pod has an emptyDir volume.
+func usesEmptyDir(pod *corev1.Pod) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// setUnschedulable cordons or uncordons a node.
+func setUnschedulable(ctx context.Context, clientset kubernetes.Interface, node string, unschedulable bool, dryRun dryRunStrategy) error {
+	verb := "cordoned"
+	if !unschedulable {
+		verb = "uncordoned"
+	}
+	if dryRun != dryRunClient {
+		patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+		_, err := clientset.CoreV1().Nodes().Patch(ctx, node, types.StrategicMergePatchType, []byte(patch),
+			metav1.PatchOptions{DryRun: dryRun.serverDryRun()})
+		if err != nil {
+			return fmt.Errorf("failed to mark node %s %s: %w", node, verb, err)
+		}
+	}
+	printChange(dryRun, "node/%s %s", node, verb)
+	return nil
+}
+
+// evictPod evicts a pod through the eviction API, retrying while a PodDisruptionBudget
+// refuses it, until --timeout.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, dryRun dryRunStrategy) error {
+	if dryRun == dryRunClient {
+		printChange(dryRun, "pod/%s evicted", pod.Name)
+		return nil
+	}
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		DeleteOptions: &metav1.DeleteOptions{DryRun: dryRun.serverDryRun()},
+	}
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, drainTimeout, true, func(ctx context.Context) (bool, error) {
+		lastErr = clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+		switch {
+		case lastErr == nil, apierrors.IsNotFound(lastErr):
+			return true, nil
+		case apierrors.IsTooManyRequests(lastErr):
+			// The budget allows no disruption right now.
+			return false, nil
+		default:
+			return false, lastErr
+		}
+	})
+	if err != nil {
+		if lastErr == nil {
+			lastErr = err
+		}
+		return fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, lastErr)
+	}
+	printChange(dryRun, "pod/%s evicted", pod.Name)
+	return nil
+}
+
+// printEvictionTable lists the pods a drain evicts; pods whose budget allows no
+// disruption right now are red. Pods it refuses to evict follow in a table of their own.
+func printEvictionTable(nodes []string, evictions []EvictionInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	okColor := color.New(color.FgGreen)
+	blockedColor := color.New(color.FgRed)
+
+	t := newTable("NAME", "NAMESPACE", "NODE NAME", "OWNER", "PDB")
+	refused := newTable("NAME", "NAMESPACE", "NODE NAME", "OWNER", "NOT EVICTED BECAUSE")
+	for _, e := range evictions {
+		if e.Refused != "" {
+			refused.addRow(e.Pod.Name, e.Pod.Namespace, e.Pod.Spec.NodeName, e.Owner, e.Refused)
+			refused.colorCell(4, blockedColor)
+			continue
+		}
+		pdb := "-"
+		if e.PDB != "" {
+			pdb = fmt.Sprintf("%s (%d allowed)", e.PDB, e.DisruptionsAllowed)
+		}
+		t.addRow(e.Pod.Name, e.Pod.Namespace, e.Pod.Spec.NodeName, e.Owner, pdb)
+		switch {
+		case e.PDB != "" && e.DisruptionsAllowed == 0:
+			t.colorCell(4, blockedColor)
+		case e.PDB != "":
+			t.colorCell(4, okColor)
+		}
+	}
+	t.print(color.Output)
+	if len(refused.rows) > 0 {
+		refused.print(color.Output)
+	}
+	headerColor.Printf("Nodes to cordon: %s\n", strings.Join(nodes, ", "))
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// dryRunStrategy is the --dry-run mode of a subcommand that changes the cluster.
+type dryRunStrategy int
+
+const (
+	// dryRunNone applies the change.
+	dryRunNone dryRunStrategy = iota
+	// dryRunClient only prints what would be changed, without calling the API.
+	dryRunClient
+	// dryRunServer sends the change with dryRun=All, so the API server validates
+	// and admits it without persisting anything.
+	dryRunServer
+)
+
+// dryRunFlag holds the raw --dry-run value.
+var dryRunFlag string
+
+// addDryRunFlag registers --dry-run on a subcommand that mutates the cluster. Every such
+// subcommand (scale, drain, uncordon, dns, connect, sniff, node-shell) must register it.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&dryRunFlag, "dry-run", "none",
+		`Must be "none", "server", or "client". With client, only print what would be changed. With server, submit the changes to the API server without persisting them.`)
+	// A bare --dry-run means --dry-run=client, like kubectl.
+	cmd.Flags().Lookup("dry-run").NoOptDefVal = "client"
+}
+
+// getDryRunStrategy parses the --dry-run flag.
+func getDryRunStrategy() (dryRunStrategy, error) {
+	switch dryRunFlag {
+	case "", "none":
+		return dryRunNone, nil
+	case "client":
+		return dryRunClient, nil
+	case "server":
+		return dryRunServer, nil
+	default:
+		return dryRunNone, fmt.Errorf(`invalid --dry-run value %q, must be "none", "server", or "client"`, dryRunFlag)
+	}
+}
+
+// serverDryRun returns the DryRun field to set on create/update/patch/delete options.
+func (s dryRunStrategy) serverDryRun() []string {
+	if s == dryRunServer {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// printChange prints one change made (or, in a dry run, that would be made), in the
+// "<kind>/<name> <verb>" form kubectl uses, e.g. "deployment.apps/web scaled (dry run)".
+func printChange(s dryRunStrategy, format string, args ...interface{}) {
+	switch s {
+	case dryRunClient:
+		format += " (dry run)"
+	case dryRunServer:
+		format += " (server dry run)"
+	}
+	fmt.Printf(format+"\n", args...)
+}
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin. Anything
+// but y or yes, including an empty line, is a no.
+func confirm(question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read the answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	fmt.Fprintln(os.Stderr, "Aborted.")
+	return false, nil
+}
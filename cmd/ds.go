@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// DsNodeInfo holds the coverage of one DaemonSet on one node.
+type DsNodeInfo struct {
+	DaemonSet string
+	Namespace string
+	NodeName  string
+	Pod       string
+	Status    string
+	Reason    string
+}
+
+// dsMissingOnly limits the ds output to nodes without a ready pod.
+var dsMissingOnly bool
+
+// dsCmd shows, per DaemonSet, which nodes are covered by a ready pod.
+var dsCmd = &cobra.Command{
+	Use:   "ds [SEARCH_PATTERN]",
+	Short: "Show node coverage of DaemonSets containing [SEARCH_PATTERN] and why nodes are missing a pod.",
+	RunE:  runDsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(dsCmd, "DaemonSets")
+	dsCmd.Flags().BoolVar(&dsMissingOnly, "missing", false,
+		"Only show nodes that don't have a ready pod.")
+}
+
+// daemonSetDefaultTolerations are the taints the DaemonSet controller tolerates on its own,
+// regardless of what the pod template declares.
+var daemonSetDefaultTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// runDsFunc returns a function that compares the nodes of the cluster against the
+// pods of every matching DaemonSet.
+func runDsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper ds fluent-bit\nor:\n  kubectl helper ds -n kube-system proxy")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		daemonSets, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.DaemonSet, error) {
+			list, err := clientset.AppsV1().DaemonSets(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve daemonsets: %w", err)
+		}
+
+		var matching []appsv1.DaemonSet
+		for _, ds := range daemonSets {
+			if matchesPattern(ds.Name, searchTerm) {
+				matching = append(matching, ds)
+			}
+		}
+		if len(matching) == 0 {
+			fmt.Printf("No daemonsets found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+		nodes := nodeList.Items
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+		for _, ds := range matching {
+			selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+			if err != nil {
+				return fmt.Errorf("invalid selector on daemonset %s/%s: %w", ds.Namespace, ds.Name, err)
+			}
+			podList, err := clientset.CoreV1().Pods(ds.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+			if err != nil {
+				return fmt.Errorf("failed to retrieve pods: %w", err)
+			}
+
+			// Only pods actually owned by this DaemonSet count towards its coverage.
+			podsByNode := map[string]corev1.Pod{}
+			for _, pod := range podList.Items {
+				for _, ref := range pod.OwnerReferences {
+					if ref.UID == ds.UID && pod.Spec.NodeName != "" {
+						podsByNode[pod.Spec.NodeName] = pod
+					}
+				}
+			}
+
+			var rows []DsNodeInfo
+			for i := range nodes {
+				rows = append(rows, dsNodeCoverage(ds, &nodes[i], podsByNode))
+			}
+			printDsTable(ds, rows)
+		}
+		return nil
+	}
+}
+
+// dsNodeCoverage works out whether ds has a ready pod on node, and if not, why.
+func dsNodeCoverage(ds appsv1.DaemonSet, node *corev1.Node, podsByNode map[string]corev1.Pod) DsNodeInfo {
+	row := DsNodeInfo{
+		DaemonSet: ds.Name,
+		Namespace: ds.Namespace,
+		NodeName:  node.Name,
+		Pod:       "<none>",
+	}
+
+	if pod, ok := podsByNode[node.Name]; ok {
+		row.Pod = pod.Name
+		if isPodReady(&pod) {
+			row.Status = "Ready"
+		} else {
+			row.Status = "NotReady"
+			row.Reason = fmt.Sprintf("pod is %s", pod.Status.Phase)
+		}
+		return row
+	}
+
+	// nodeSelector and untolerated taints mean the DaemonSet deliberately skips the node.
+	var reasons []string
+	for key, value := range ds.Spec.Template.Spec.NodeSelector {
+		if nodeValue, ok := node.Labels[key]; !ok || nodeValue != value {
+			reasons = append(reasons, fmt.Sprintf("nodeSelector %s=%s", key, value))
+		}
+	}
+
+	var tolerations []corev1.Toleration
+	tolerations = append(tolerations, ds.Spec.Template.Spec.Tolerations...)
+	tolerations = append(tolerations, daemonSetDefaultTolerations...)
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !toleratesTaint(tolerations, taint) {
+			reasons = append(reasons, fmt.Sprintf("taint %s", taint.ToString()))
+		}
+	}
+
+	if len(reasons) > 0 {
+		sort.Strings(reasons)
+		row.Status = "Excluded"
+		row.Reason = strings.Join(reasons, ", ")
+		return row
+	}
+
+	// Anything else is a real coverage gap.
+	row.Status = "Missing"
+	if node.Spec.Unschedulable {
+		row.Reason = "node unschedulable"
+	} else {
+		row.Reason = "not scheduled yet"
+	}
+	return row
+}
+
+// toleratesTaint reports whether any of the tolerations tolerates the taint.
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// printDsTable prints the node coverage of one DaemonSet, with coverage gaps in red.
+func printDsTable(ds appsv1.DaemonSet, rows []DsNodeInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	missingColor := color.New(color.FgRed, color.Bold)
+	notReadyColor := color.New(color.FgYellow, color.Bold)
+
+	ready, eligible := 0, 0
+	for _, r := range rows {
+		if r.Status != "Excluded" {
+			eligible++
+		}
+		if r.Status == "Ready" {
+			ready++
+		}
+	}
+
+	fmt.Println()
+	headerColor.Printf("%s/%s: %d/%d eligible nodes ready", ds.Namespace, ds.Name, ready, eligible)
+	if gaps := eligible - ready; gaps > 0 {
+		missingColor.Printf(" (%d gap(s))", gaps)
+	}
+	fmt.Println()
+
+	headerColor.Printf("%-30s %-45s %-10s %-40s\n", "NODE NAME", "POD", "STATUS", "REASON")
+	line := strings.Repeat("-", 130)
+	lineColor.Println(line)
+
+	for _, r := range rows {
+		if dsMissingOnly && r.Status == "Ready" {
+			continue
+		}
+		fmt.Printf("%-30s %-45s ", r.NodeName, r.Pod)
+		status := fmt.Sprintf("%-10s", r.Status)
+		switch r.Status {
+		case "Missing":
+			missingColor.Print(status)
+		case "NotReady":
+			notReadyColor.Print(status)
+		default:
+			fmt.Print(status)
+		}
+		fmt.Printf(" %-40s\n", r.Reason)
+	}
+	fmt.Println()
+}
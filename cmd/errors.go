@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// errorFormat selects how a failed command reports its error on stderr: "text" or "json".
+var errorFormat string
+
+// StructuredError is the --error-format json representation of a failed command, so
+// wrapping automation can tell auth failures, missing objects and connectivity apart.
+type StructuredError struct {
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Status    int32  `json:"status,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+}
+
+// errorHints explains the common API failure reasons.
+var errorHints = map[metav1.StatusReason]string{
+	metav1.StatusReasonUnauthorized:    "your credentials are missing or expired, log in to the cluster again",
+	metav1.StatusReasonForbidden:       "you lack RBAC permissions for this, check with kubectl auth can-i",
+	metav1.StatusReasonNotFound:        "the object doesn't exist, check the name and the namespace (-n)",
+	metav1.StatusReasonTimeout:         "the request timed out, retry or narrow it down with -n",
+	metav1.StatusReasonServerTimeout:   "the API server is overloaded, retry later",
+	metav1.StatusReasonTooManyRequests: "the API server is throttling requests, retry later",
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text",
+		"How to report errors on stderr. One of: text, json.")
+}
+
+// printError reports err on stderr in the requested --error-format.
+func printError(err error) {
+	if errorFormat != "json" {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	out, marshalErr := json.Marshal(newStructuredError(err))
+	if marshalErr != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(out))
+}
+
+// newStructuredError classifies err by the API status it wraps, or as a connection failure.
+func newStructuredError(err error) StructuredError {
+	se := StructuredError{
+		Reason:  string(metav1.StatusReasonUnknown),
+		Message: err.Error(),
+	}
+
+	// Per-namespace failures are aggregated; the first one stands for the rest.
+	cause := err
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) && len(agg.Errors()) > 0 {
+		cause = agg.Errors()[0]
+	}
+	se.Namespace = errorNamespace(cause)
+
+	var status apierrors.APIStatus
+	var netErr net.Error
+	switch {
+	case errors.As(cause, &status):
+		s := status.Status()
+		se.Status = s.Code
+		if s.Reason != "" {
+			se.Reason = string(s.Reason)
+		}
+		se.Hint = errorHints[s.Reason]
+	case errors.As(cause, &netErr), strings.Contains(cause.Error(), "connection refused"), strings.Contains(cause.Error(), "no such host"):
+		se.Reason = "ConnectionFailed"
+		se.Hint = "the API server is unreachable, check the current context and your network/VPN"
+	}
+	return se
+}
+
+// namespaceError marks err as the failure of a request in Namespace, so --error-format json
+// can report which namespace failed rather than every namespace the command targeted.
+type namespaceError struct {
+	Namespace string
+	Err       error
+}
+
+func (e *namespaceError) Error() string { return e.Err.Error() }
+
+func (e *namespaceError) Unwrap() error { return e.Err }
+
+// errorNamespace returns the namespace whose request failed with err, "" when unknown.
+func errorNamespace(err error) string {
+	var nsErr *namespaceError
+	if errors.As(err, &nsErr) {
+		return nsErr.Namespace
+	}
+	return ""
+}
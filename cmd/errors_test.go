@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestNewStructuredError(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+	tests := []struct {
+		name          string
+		err           error
+		wantReason    string
+		wantStatus    int32
+		wantNamespace string
+	}{
+		{
+			name:       "plain error",
+			err:        errors.New("boom"),
+			wantReason: string(metav1.StatusReasonUnknown),
+		},
+		{
+			name:       "api error without namespace",
+			err:        fmt.Errorf("failed to retrieve pods: %w", forbidden),
+			wantReason: "Forbidden",
+			wantStatus: 403,
+		},
+		{
+			name:          "failed namespace",
+			err:           fmt.Errorf("failed to retrieve pods: %w", &namespaceError{Namespace: "dev", Err: forbidden}),
+			wantReason:    "Forbidden",
+			wantStatus:    403,
+			wantNamespace: "dev",
+		},
+		{
+			name: "first aggregated failure",
+			err: fmt.Errorf("failed to retrieve pods: %w", utilerrors.NewAggregate([]error{
+				&namespaceError{Namespace: "dev", Err: apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")},
+				&namespaceError{Namespace: "prod", Err: forbidden},
+			})),
+			wantReason:    "NotFound",
+			wantStatus:    404,
+			wantNamespace: "dev",
+		},
+		{
+			name:       "connection refused",
+			err:        errors.New("dial tcp 127.0.0.1:6443: connect: connection refused"),
+			wantReason: "ConnectionFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newStructuredError(tt.err)
+			if got.Reason != tt.wantReason || got.Status != tt.wantStatus || got.Namespace != tt.wantNamespace {
+				t.Errorf("newStructuredError() = %+v, want reason %s, status %d, namespace %q", got, tt.wantReason, tt.wantStatus, tt.wantNamespace)
+			}
+			if got.Message != tt.err.Error() {
+				t.Errorf("newStructuredError() message = %q, want %q", got.Message, tt.err.Error())
+			}
+		})
+	}
+}
+
+// TestNamespaceErrorUnwraps checks that wrapping keeps the API status visible to the
+// callers that skip forbidden namespaces.
+func TestNamespaceErrorUnwraps(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+	err := &namespaceError{Namespace: "dev", Err: forbidden}
+	if !isForbidden(err) {
+		t.Error("isForbidden() = false for a wrapped forbidden error")
+	}
+	if err.Error() != forbidden.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), forbidden.Error())
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// execInPod runs command in a container of pod and returns its stdout and stderr.
+func execInPod(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pod *corev1.Pod, container string, command []string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	err := streamExecInPod(ctx, configFlags, clientset, pod, container, command, &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+// streamExecInPod runs command in a container of pod, streaming its output to stdout and stderr.
+func streamExecInPod(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pod *corev1.Pod, container string, command []string, stdout, stderr io.Writer) error {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to exec into %s: %w", pod.Name, err)
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+}
+
+// interactiveExecInPod runs command in a container of pod attached to this terminal, like
+// kubectl exec -it. Without a terminal on stdin, stdin and the output are streamed as is.
+func interactiveExecInPod(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pod *corev1.Pod, container string, command []string) error {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	tty := term.IsTerminal(int(os.Stdin.Fd()))
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			// A terminal merges stderr into stdout.
+			Stderr: !tty,
+			TTY:    tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to exec into %s: %w", pod.Name, err)
+	}
+	opts := remotecommand.StreamOptions{Stdin: os.Stdin, Stdout: terminalStdout, Stderr: os.Stderr, Tty: tty}
+	if tty {
+		fd := int(os.Stdin.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, state)
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		opts.TerminalSizeQueue = &terminalSizeQueue{ctx: ctx, fd: fd}
+	}
+	return executor.StreamWithContext(ctx, opts)
+}
+
+// terminalSizeQueue hands the size of the local terminal to the remote one. It polls for
+// changes, since not every platform has a resize signal.
+type terminalSizeQueue struct {
+	ctx  context.Context
+	fd   int
+	last remotecommand.TerminalSize
+}
+
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	for {
+		if width, height, err := term.GetSize(q.fd); err == nil {
+			size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+			if size != q.last {
+				q.last = size
+				return &size
+			}
+		}
+		select {
+		case <-q.ctx.Done():
+			return nil
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// commandMissing reports whether an exec failed because the binary doesn't exist in the
+// image, e.g. in distroless containers.
+func commandMissing(err error, stderr string) bool {
+	var exitErr utilexec.CodeExitError
+	if errors.As(err, &exitErr) && exitErr.Code == 127 {
+		return true
+	}
+	for _, s := range []string{err.Error(), stderr} {
+		if strings.Contains(s, "executable file not found") || strings.Contains(s, "no such file or directory") {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultContainer returns the container kubectl would pick: the one named by the
+// kubectl.kubernetes.io/default-container annotation, otherwise the first one.
+func defaultContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations["kubectl.kubernetes.io/default-container"]; name != "" {
+		return name
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// addDebugContainer injects an ephemeral container running image into pod, sharing the
+// process namespace of target, and waits until it runs. It returns the container name, or
+// "" in a dry run, which adds nothing to exec into.
+// Ephemeral containers can't be removed again; they stop when their command ends.
+func addDebugContainer(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, target, image string, command []string, dryRun dryRunStrategy) (string, error) {
+	name := "helper-debug-" + rand.String(5)
+	updated := pod.DeepCopy()
+	updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  command,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	})
+	if dryRun != dryRunClient {
+		if _, err := clientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, updated,
+			metav1.UpdateOptions{DryRun: dryRun.serverDryRun()}); err != nil {
+			return "", fmt.Errorf("failed to add debug container to %s: %w", pod.Name, err)
+		}
+	}
+	if dryRun != dryRunNone {
+		printChange(dryRun, "pod/%s ephemeral container %s added", pod.Name, name)
+		return "", nil
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, time.Second, 90*time.Second, true, func(ctx context.Context) (bool, error) {
+		current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, st := range current.Status.EphemeralContainerStatuses {
+			if st.Name != name {
+				continue
+			}
+			if st.State.Running != nil {
+				return true, nil
+			}
+			if st.State.Terminated != nil {
+				return false, fmt.Errorf("debug container terminated: %s", st.State.Terminated.Reason)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("debug container in %s didn't start: %w", pod.Name, err)
+	}
+	return name, nil
+}
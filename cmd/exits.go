@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ExitInfo holds the last termination of one container.
+type ExitInfo struct {
+	Pod        string
+	Namespace  string
+	Container  string
+	ExitCode   int32
+	Signal     string
+	Reason     string
+	FinishedAt time.Time
+	Restarts   int32
+}
+
+// exitSignals names the signals containers are usually killed with.
+var exitSignals = map[int32]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	6:  "SIGABRT",
+	9:  "SIGKILL",
+	11: "SIGSEGV",
+	15: "SIGTERM",
+}
+
+// exitsCmd reports the last terminated state of the containers of the matching pods.
+var exitsCmd = &cobra.Command{
+	Use:   "exits [SEARCH_PATTERN]",
+	Short: "Show the last exit code, signal and reason of the containers of pods containing [SEARCH_PATTERN].",
+	RunE:  runExitsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(exitsCmd, "pods")
+}
+
+// runExitsFunc returns a function that lists every container that terminated at least once,
+// followed by a summary aggregated per container and reason.
+func runExitsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper exits api\nor:\n  kubectl helper exits -n dev api")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var exits []ExitInfo
+		for _, pod := range pods {
+			for _, st := range pod.Status.ContainerStatuses {
+				// A container that is terminated right now hasn't got a "last" state yet.
+				term := st.LastTerminationState.Terminated
+				if st.State.Terminated != nil {
+					term = st.State.Terminated
+				}
+				if term == nil {
+					continue
+				}
+				exits = append(exits, ExitInfo{
+					Pod:        pod.Name,
+					Namespace:  pod.Namespace,
+					Container:  st.Name,
+					ExitCode:   term.ExitCode,
+					Signal:     exitSignal(term),
+					Reason:     term.Reason,
+					FinishedAt: term.FinishedAt.Time,
+					Restarts:   st.RestartCount,
+				})
+			}
+		}
+
+		if len(exits) == 0 {
+			fmt.Printf("No terminated containers found in the %d pod(s) matching the pattern: %s\n", len(pods), searchTerm)
+			return nil
+		}
+
+		// Most recent terminations first.
+		sort.Slice(exits, func(i, j int) bool { return exits[i].FinishedAt.After(exits[j].FinishedAt) })
+		printExitTable(exits)
+		printExitSummary(exits)
+		return nil
+	}
+}
+
+// exitSignal returns the name of the signal that terminated the container. The kubelet
+// rarely fills in Signal, so exit codes above 128 (128 + signal number) are decoded too.
+func exitSignal(term *corev1.ContainerStateTerminated) string {
+	signal := term.Signal
+	if signal == 0 && term.ExitCode > 128 {
+		signal = term.ExitCode - 128
+	}
+	if signal == 0 {
+		return "-"
+	}
+	if name, ok := exitSignals[signal]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", signal)
+}
+
+// exitReasonColor picks the color of a termination reason.
+func exitReasonColor(e ExitInfo) *color.Color {
+	switch {
+	case e.Reason == "OOMKilled":
+		return color.New(color.FgRed, color.Bold)
+	case e.ExitCode != 0:
+		return color.New(color.FgYellow)
+	}
+	return color.New(color.Reset)
+}
+
+// printExitTable prints the last termination of every container.
+func printExitTable(exits []ExitInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-20s %-10s %-8s %-20s %-12s %-9s\n",
+		"POD", "NAMESPACE", "CONTAINER", "EXIT CODE", "SIGNAL", "REASON", "FINISHED", "RESTARTS")
+
+	line := strings.Repeat("-", 135)
+	lineColor.Println(line)
+
+	for _, e := range exits {
+		finished := "<unknown>"
+		if !e.FinishedAt.IsZero() {
+			finished = duration.HumanDuration(time.Since(e.FinishedAt)) + " ago"
+		}
+		fmt.Printf("%-35s %-15s %-20s %-10d %-8s ", e.Pod, e.Namespace, e.Container, e.ExitCode, e.Signal)
+		exitReasonColor(e).Printf("%-20s", e.Reason)
+		fmt.Printf(" %-12s %-9d\n", finished, e.Restarts)
+	}
+	fmt.Println()
+}
+
+// printExitSummary aggregates the terminations per container name, reason and exit code,
+// so a pattern like "app was OOMKilled in 7 of 10 replicas" stands out.
+func printExitSummary(exits []ExitInfo) {
+	type summaryKey struct {
+		container string
+		reason    string
+		exitCode  int32
+	}
+	type summary struct {
+		ExitInfo
+		pods     int
+		restarts int32
+	}
+
+	byKey := map[summaryKey]*summary{}
+	var rows []*summary
+	for _, e := range exits {
+		key := summaryKey{e.Container, e.Reason, e.ExitCode}
+		s, ok := byKey[key]
+		if !ok {
+			s = &summary{ExitInfo: e}
+			byKey[key] = s
+			rows = append(rows, s)
+		}
+		s.pods++
+		s.restarts += e.Restarts
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].pods > rows[j].pods })
+
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+
+	headerColor.Printf("%-20s %-20s %-10s %-6s %-9s\n", "CONTAINER", "REASON", "EXIT CODE", "PODS", "RESTARTS")
+	line := strings.Repeat("-", 70)
+	lineColor.Println(line)
+	for _, s := range rows {
+		fmt.Printf("%-20s ", s.Container)
+		exitReasonColor(s.ExitInfo).Printf("%-20s", s.Reason)
+		fmt.Printf(" %-10d %-6d %-9d\n", s.ExitCode, s.pods, s.restarts)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+// ExportInfo holds one exported workload manifest.
+type ExportInfo struct {
+	Kind      string
+	Name      string
+	Namespace string
+	File      string
+}
+
+// exportDir is the directory the manifests are written to, one subdirectory per namespace.
+var exportDir string
+
+// exportAnnotations are set by controllers and kubectl and would be stale in another cluster.
+var exportAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// exportJobLabels are generated for every Job and are rejected when applied again.
+var exportJobLabels = []string{
+	"controller-uid",
+	"job-name",
+	"batch.kubernetes.io/controller-uid",
+	"batch.kubernetes.io/job-name",
+}
+
+// exportCmd writes the cleaned manifests of the workloads owning the matching pods.
+var exportCmd = &cobra.Command{
+	Use:   "export [SEARCH_PATTERN]",
+	Short: "Write the manifests of the workloads owning pods containing [SEARCH_PATTERN], without server-set fields, to a directory per namespace.",
+	RunE:  runExportFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(exportCmd, "pods")
+	exportCmd.Flags().StringVarP(&exportDir, "dir", "d", "export",
+		"Directory to write the manifests to, as <dir>/<namespace>/<kind>-<name>.yaml.")
+}
+
+// runExportFunc returns a function that resolves the top-level owner of every matching pod
+// and writes each owner once.
+func runExportFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper export api\nor:\n  kubectl helper export -n dev --dir backup \"\"")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+		resolver, err := newOwnerResolver(configFlags)
+		if err != nil {
+			return err
+		}
+
+		warnColor := color.New(color.FgYellow)
+		seen := map[string]bool{}
+		var objects []*unstructured.Unstructured
+		for i := range pods {
+			pod := &pods[i]
+			var obj *unstructured.Unstructured
+			ref := metav1.GetControllerOf(pod)
+			if ref == nil {
+				// A bare pod is its own workload.
+				content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+				if err != nil {
+					return fmt.Errorf("failed to convert pod %s: %w", pod.Name, err)
+				}
+				obj = &unstructured.Unstructured{Object: content}
+				obj.SetAPIVersion("v1")
+				obj.SetKind("Pod")
+			} else {
+				top, topRef := resolver.top(ctx, pod.Namespace, *ref)
+				if top == nil {
+					warnColor.Fprintf(os.Stderr, "Skipping %s/%s: can't read its owner %s/%s\n", pod.Namespace, pod.Name, ref.Kind, ref.Name)
+					continue
+				}
+				if top.GetKind() != topRef.Kind || top.GetName() != topRef.Name {
+					warnColor.Fprintf(os.Stderr, "Exporting %s/%s instead of its unreadable owner %s/%s\n",
+						top.GetKind(), top.GetName(), topRef.Kind, topRef.Name)
+				}
+				obj = top.DeepCopy()
+			}
+
+			key := obj.GetNamespace() + "/" + obj.GetKind() + "/" + obj.GetName()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			objects = append(objects, obj)
+		}
+
+		var infos []ExportInfo
+		for _, obj := range objects {
+			cleanExportedObject(obj)
+			data, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+			}
+			dir := filepath.Join(exportDir, obj.GetNamespace())
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", dir, err)
+			}
+			path := filepath.Join(dir, strings.ToLower(obj.GetKind())+"-"+obj.GetName()+".yaml")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			infos = append(infos, ExportInfo{Kind: obj.GetKind(), Name: obj.GetName(), Namespace: obj.GetNamespace(), File: path})
+		}
+		historyMatches = len(infos)
+		if len(infos) == 0 {
+			fmt.Printf("No workloads exported for pods matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].File < infos[j].File
+		})
+		t := newTable("KIND", "NAME", "NAMESPACE", "FILE")
+		for _, e := range infos {
+			t.addRow(e.Kind, e.Name, e.Namespace, e.File)
+		}
+		t.print(color.Output)
+		return nil
+	}
+}
+
+// cleanExportedObject strips what the API server and controllers set, so the manifest can
+// be applied to another cluster or after the object is gone.
+func cleanExportedObject(obj *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(obj.Object, "status")
+	for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink", "deletionTimestamp", "deletionGracePeriodSeconds"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	// The owners' uids don't exist elsewhere, and the garbage collector deletes objects
+	// whose owners are gone. The name is kept, so generateName is only noise.
+	unstructured.RemoveNestedField(obj.Object, "metadata", "ownerReferences")
+	unstructured.RemoveNestedField(obj.Object, "metadata", "generateName")
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		for _, a := range exportAnnotations {
+			delete(annotations, a)
+		}
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+	}
+
+	switch obj.GetKind() {
+	case "Pod":
+		// The scheduler picks a node again.
+		unstructured.RemoveNestedField(obj.Object, "spec", "nodeName")
+	case "Job":
+		// The selector and its labels are generated from the uid unless set by hand.
+		if manual, _, _ := unstructured.NestedBool(obj.Object, "spec", "manualSelector"); !manual {
+			unstructured.RemoveNestedField(obj.Object, "spec", "selector")
+			for _, label := range exportJobLabels {
+				unstructured.RemoveNestedField(obj.Object, "spec", "template", "metadata", "labels", label)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// graphNodeKind tells the renderers how to draw a vertex.
+type graphNodeKind int
+
+const (
+	graphPod graphNodeKind = iota
+	graphNode
+	graphOwner
+	graphService
+)
+
+// graphVertex is one pod, node, owner or service in the topology graph.
+type graphVertex struct {
+	ID    string
+	Kind  graphNodeKind
+	Label []string
+}
+
+// graphEdge connects two vertices by ID.
+type graphEdge struct {
+	From string
+	To   string
+}
+
+// podGraph is the topology of the matched pods: owner -> pod, service -> pod and pod -> node.
+type podGraph struct {
+	Vertices []graphVertex
+	Edges    []graphEdge
+}
+
+// servicesSelectingPods returns the services whose selector matches at least one of the pods.
+func servicesSelectingPods(ctx context.Context, clientset kubernetes.Interface, pods []PodInfo) ([]corev1.Service, error) {
+	namespaces := map[string]bool{}
+	for _, p := range pods {
+		namespaces[p.Namespace] = true
+	}
+
+	var selecting []corev1.Service
+	for ns := range namespaces {
+		list, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve services: %w", err)
+		}
+		for _, svc := range list.Items {
+			for _, p := range pods {
+				if serviceSelects(&svc, p) {
+					selecting = append(selecting, svc)
+					break
+				}
+			}
+		}
+	}
+	return selecting, nil
+}
+
+// serviceSelects reports whether svc routes traffic to the pod.
+func serviceSelects(svc *corev1.Service, p PodInfo) bool {
+	if svc.Namespace != p.Namespace || len(svc.Spec.Selector) == 0 {
+		return false
+	}
+	return labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(p.Labels))
+}
+
+// buildPodGraph turns the pods and the services in front of them into a graph.
+func buildPodGraph(pods []PodInfo, services []corev1.Service) podGraph {
+	var g podGraph
+	seen := map[string]bool{}
+	addVertex := func(v graphVertex) {
+		if !seen[v.ID] {
+			seen[v.ID] = true
+			g.Vertices = append(g.Vertices, v)
+		}
+	}
+
+	for _, p := range pods {
+		podID := "pod/" + p.Namespace + "/" + p.Name
+		addVertex(graphVertex{ID: podID, Kind: graphPod, Label: []string{p.Name, p.IP}})
+
+		if p.NodeName != "" {
+			nodeID := "node/" + p.NodeName
+			addVertex(graphVertex{ID: nodeID, Kind: graphNode, Label: []string{p.NodeName, p.NodeIP}})
+			g.Edges = append(g.Edges, graphEdge{From: podID, To: nodeID})
+		}
+
+		if p.Owner != "" {
+			ownerID := "owner/" + p.Namespace + "/" + p.Owner
+			kind, name, _ := strings.Cut(p.Owner, "/")
+			addVertex(graphVertex{ID: ownerID, Kind: graphOwner, Label: []string{kind, name}})
+			g.Edges = append(g.Edges, graphEdge{From: ownerID, To: podID})
+		}
+
+		for i := range services {
+			if serviceSelects(&services[i], p) {
+				svcID := "svc/" + services[i].Namespace + "/" + services[i].Name
+				addVertex(graphVertex{ID: svcID, Kind: graphService, Label: []string{"svc " + services[i].Name, services[i].Spec.ClusterIP}})
+				g.Edges = append(g.Edges, graphEdge{From: svcID, To: podID})
+			}
+		}
+	}
+
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// printDotGraph renders the graph in Graphviz DOT, e.g. for `| dot -Tpng > pods.png`.
+func printDotGraph(out io.Writer, g podGraph) {
+	shapes := map[graphNodeKind]string{
+		graphPod:     "box",
+		graphNode:    "box3d",
+		graphOwner:   "component",
+		graphService: "ellipse",
+	}
+
+	fmt.Fprintln(out, "digraph pods {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+	fmt.Fprintln(out, "  node [fontname=\"Helvetica\"];")
+	for _, v := range g.Vertices {
+		fmt.Fprintf(out, "  %q [label=%q, shape=%s];\n", v.ID, strings.Join(nonEmpty(v.Label), "\n"), shapes[v.Kind])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(out, "  %q -> %q;\n", e.From, e.To)
+	}
+	fmt.Fprintln(out, "}")
+}
+
+// printMermaidGraph renders the graph as a Mermaid flowchart for Markdown documents.
+func printMermaidGraph(out io.Writer, g podGraph) {
+	// Mermaid IDs can't contain slashes or dots, so vertices get short generated IDs.
+	ids := make(map[string]string, len(g.Vertices))
+	fmt.Fprintln(out, "graph LR")
+	for i, v := range g.Vertices {
+		id := fmt.Sprintf("v%d", i)
+		ids[v.ID] = id
+		label := strings.ReplaceAll(strings.Join(nonEmpty(v.Label), "<br/>"), `"`, "#quot;")
+		switch v.Kind {
+		case graphNode:
+			fmt.Fprintf(out, "  %s[(\"%s\")]\n", id, label)
+		case graphOwner:
+			fmt.Fprintf(out, "  %s[[\"%s\"]]\n", id, label)
+		case graphService:
+			fmt.Fprintf(out, "  %s([\"%s\"])\n", id, label)
+		default:
+			fmt.Fprintf(out, "  %s[\"%s\"]\n", id, label)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(out, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+}
+
+// nonEmpty drops the empty strings from parts.
+func nonEmpty(parts []string) []string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// historyLimit is the number of invocations kept in the history file.
+const historyLimit = 200
+
+// historyTrimSize is the file size from which the history is cut back to historyLimit
+// entries; entries are appended until then, so concurrent invocations don't lose any.
+const historyTrimSize = 256 << 10
+
+// historySecretFlags have their values replaced in the recorded args.
+var historySecretFlags = []string{"--token", "--password", "--notify-url"}
+
+// redactedValue replaces the values of historySecretFlags.
+const redactedValue = "REDACTED"
+
+// HistoryEntry is one recorded helper invocation.
+type HistoryEntry struct {
+	Time      time.Time `json:"time"`
+	Args      []string  `json:"args"`
+	Command   string    `json:"command"`
+	Pattern   string    `json:"pattern,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	// Matches is the number of pods found, -1 if the command doesn't search pods.
+	Matches int    `json:"matches"`
+	Error   string `json:"error,omitempty"`
+}
+
+// historyMatches is set by the pod searches so the invocation can be recorded with its match count.
+var historyMatches = -1
+
+// historyRerun and historyListLimit are the flags of the history command.
+var (
+	historyRerun     int
+	historyListLimit int
+)
+
+// historyCmd lists and re-runs the recent helper invocations.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recent helper invocations, or re-run one with --rerun N.",
+	RunE:  runHistory,
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyRerun, "rerun", 0, "Re-run the invocation with this number.")
+	historyCmd.Flags().IntVar(&historyListLimit, "limit", 20, "Number of recent invocations to list.")
+}
+
+// historyFilePath returns the history file next to the config file.
+func historyFilePath() (string, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "history.jsonl"), nil
+}
+
+// readHistory returns the recorded invocations, oldest first. A missing file is an empty history.
+func readHistory() ([]HistoryEntry, error) {
+	path, err := historyFilePath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e HistoryEntry
+		// A line cut short by a crash shouldn't make the whole history unreadable.
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+	return entries, scanner.Err()
+}
+
+// redactArgs returns args with the values of historySecretFlags replaced, in both the
+// --flag=value and the --flag value form.
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i := 0; i < len(redacted); i++ {
+		for _, flag := range historySecretFlags {
+			if strings.HasPrefix(redacted[i], flag+"=") {
+				redacted[i] = flag + "=" + redactedValue
+				break
+			}
+			if redacted[i] == flag && i+1 < len(redacted) {
+				redacted[i+1] = redactedValue
+				i++
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// redactedFlags returns the historySecretFlags whose value redactArgs replaced in args.
+func redactedFlags(args []string) []string {
+	var flags []string
+	for i, arg := range args {
+		for _, flag := range historySecretFlags {
+			if arg == flag+"="+redactedValue || (arg == flag && i+1 < len(args) && args[i+1] == redactedValue) {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	return flags
+}
+
+// recordHistory appends the invocation of cmd with args to the history file, keeping the last
+// historyLimit entries. It is best effort: a failure must never break the actual command.
+func recordHistory(cmd *cobra.Command, args []string, runErr error) {
+	if cmd == nil || cmd == RootCmd || cmd == historyCmd {
+		return
+	}
+	path, err := historyFilePath()
+	if err != nil {
+		return
+	}
+
+	entry := HistoryEntry{
+		Time:    time.Now(),
+		Args:    redactArgs(args),
+		Command: cmd.Name(),
+		Matches: historyMatches,
+	}
+	if positional := cmd.Flags().Args(); len(positional) > 0 {
+		entry.Pattern = positional[0]
+	}
+	if namespaces, err := targetNamespaces(configFlags); err == nil {
+		entry.Namespace = strings.Join(namespaces, ",")
+	}
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	// A single append of a whole line doesn't interleave with other invocations.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return
+	}
+	_, err = f.Write(append(line, '\n'))
+	f.Close()
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > historyTrimSize {
+		trimHistory(path)
+	}
+}
+
+// trimHistory cuts the history file back to its last historyLimit entries, replacing it
+// through a rename so readers never see half a file.
+func trimHistory(path string) {
+	entries, err := readHistory()
+	if err != nil {
+		return
+	}
+	var buf bytes.Buffer
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".history-*.jsonl")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(buf.Bytes())
+	if closeErr := tmp.Close(); err != nil || closeErr != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), path)
+}
+
+// runHistory prints the recent invocations or re-runs one of them.
+func runHistory(cmd *cobra.Command, args []string) error {
+	entries, err := readHistory()
+	if err != nil {
+		return err
+	}
+
+	if historyRerun != 0 {
+		if historyRerun < 1 || historyRerun > len(entries) {
+			return fmt.Errorf("no history entry %d, there are %d entries", historyRerun, len(entries))
+		}
+		entry := entries[historyRerun-1]
+		// The stored values are placeholders; running with them would fail or, worse, send them.
+		if flags := redactedFlags(entry.Args); len(flags) > 0 {
+			return fmt.Errorf("entry %d was run with %s, whose value isn't stored; run it again by hand:\n  kubectl helper %s",
+				historyRerun, strings.Join(flags, ", "), strings.Join(entry.Args, " "))
+		}
+		// A fresh process gets clean flag state, exactly like the original invocation.
+		self, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate the helper binary: %w", err)
+		}
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Re-running: kubectl helper %s\n", strings.Join(entry.Args, " "))
+		rerun := exec.Command(self, entry.Args...)
+		// The rerun command pages its own output.
+		rerun.Stdin, rerun.Stdout, rerun.Stderr = os.Stdin, terminalStdout, os.Stderr
+		return rerun.Run()
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No helper invocations recorded yet.")
+		return nil
+	}
+	printHistoryTable(entries)
+	return nil
+}
+
+// printHistoryTable prints the last --limit entries with the numbers --rerun accepts.
+func printHistoryTable(entries []HistoryEntry) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	errorColor := color.New(color.FgRed)
+
+	start := 0
+	if historyListLimit > 0 && len(entries) > historyListLimit {
+		start = len(entries) - historyListLimit
+	}
+
+	fmt.Println()
+	headerColor.Printf("%-5s %-20s %-12s %-25s %-20s %-8s %-60s\n", "#", "TIME", "COMMAND", "PATTERN", "NAMESPACE", "MATCHES", "ARGS")
+	line := strings.Repeat("-", 155)
+	lineColor.Println(line)
+	for i := start; i < len(entries); i++ {
+		e := entries[i]
+		namespace := e.Namespace
+		if namespace == "" {
+			namespace = "<all>"
+		}
+		matches := "-"
+		if e.Matches >= 0 {
+			matches = fmt.Sprintf("%d", e.Matches)
+		}
+		fmt.Printf("%-5d %-20s %-12s %-25s %-20s %-8s ", i+1, e.Time.Format("2006-01-02 15:04:05"), e.Command, e.Pattern, namespace, matches)
+		if e.Error != "" {
+			errorColor.Printf("%-60s\n", strings.Join(e.Args, " "))
+		} else {
+			fmt.Printf("%-60s\n", strings.Join(e.Args, " "))
+		}
+	}
+	fmt.Println()
+}
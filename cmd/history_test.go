@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{
+			name: "no secrets",
+			args: []string{"ip", "nginx", "-n", "dev"},
+			want: []string{"ip", "nginx", "-n", "dev"},
+		},
+		{
+			name: "flag=value",
+			args: []string{"ip", "--token=abc", "nginx"},
+			want: []string{"ip", "--token=REDACTED", "nginx"},
+		},
+		{
+			name: "separate value",
+			args: []string{"ip", "--password", "hunter2", "nginx"},
+			want: []string{"ip", "--password", "REDACTED", "nginx"},
+		},
+		{
+			name: "value looking like a flag",
+			args: []string{"watch", "--notify-url", "--token", "nginx"},
+			want: []string{"watch", "--notify-url", "REDACTED", "nginx"},
+		},
+		{
+			name: "flag without value",
+			args: []string{"ip", "--token"},
+			want: []string{"ip", "--token"},
+		},
+		{
+			name: "several secrets",
+			args: []string{"ip", "--token", "abc", "--password=def"},
+			want: []string{"ip", "--token", "REDACTED", "--password=REDACTED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{}, tt.args...)
+			if got := redactArgs(args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("redactArgs modified its argument: %q", args)
+			}
+		})
+	}
+}
+
+func TestRedactedFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"none", []string{"ip", "nginx"}, nil},
+		{"flag=value", []string{"ip", "--token=REDACTED"}, []string{"--token"}},
+		{"separate value", []string{"ip", "--password", "REDACTED"}, []string{"--password"}},
+		{"real value", []string{"ip", "--token", "abc"}, nil},
+		{"both", []string{"watch", "--token=REDACTED", "--notify-url", "REDACTED"}, []string{"--token", "--notify-url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactedFlags(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redactedFlags(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRedactedArgsAreNotRerun checks that every recorded secret is recognized again, so
+// --rerun never replays a placeholder as the credential.
+func TestRedactedArgsAreNotRerun(t *testing.T) {
+	for _, flag := range historySecretFlags {
+		for _, args := range [][]string{{"ip", flag, "secret"}, {"ip", flag + "=secret"}} {
+			if got := redactedFlags(redactArgs(args)); !reflect.DeepEqual(got, []string{flag}) {
+				t.Errorf("redactedFlags(redactArgs(%q)) = %q, want [%s]", args, got, flag)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ImageInfo holds the image of one container of a matched pod.
+type ImageInfo struct {
+	Pod       string
+	Namespace string
+	Container string
+	Image     string
+	// Digest is the digest of the image actually running, from the container status.
+	Digest string
+}
+
+// imagesDigests shows the running digest of every container instead of the image summary.
+var imagesDigests bool
+
+// imagesCmd lists the images used by the matching pods.
+var imagesCmd = &cobra.Command{
+	Use:   "images [SEARCH_PATTERN]",
+	Short: "List the container images of pods containing [SEARCH_PATTERN].",
+	RunE:  runImagesFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(imagesCmd, "pods")
+	imagesCmd.Flags().BoolVar(&imagesDigests, "digests", false,
+		"Show the image digest each container is actually running next to the tag in the spec.")
+}
+
+// runImagesFunc returns a function that prints the images of the matching pods, either
+// summarized per image or, with --digests, per container with the running digest.
+func runImagesFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper images api\nor:\n  kubectl helper images -n dev --digests api")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var images []ImageInfo
+		for _, pod := range pods {
+			digests := map[string]string{}
+			for _, st := range pod.Status.ContainerStatuses {
+				digests[st.Name] = imageDigest(st.ImageID)
+			}
+			for _, c := range pod.Spec.Containers {
+				digest := digests[c.Name]
+				if digest == "" {
+					digest = "<not running>"
+				}
+				images = append(images, ImageInfo{
+					Pod:       pod.Name,
+					Namespace: pod.Namespace,
+					Container: c.Name,
+					Image:     c.Image,
+					Digest:    digest,
+				})
+			}
+		}
+
+		if imagesDigests {
+			printImageDigestTable(images)
+		} else {
+			printImageSummary(images)
+		}
+		return nil
+	}
+}
+
+// imageDigest extracts "sha256:..." from a container status imageID such as
+// "docker.io/library/nginx@sha256:..." or "docker-pullable://nginx@sha256:...".
+func imageDigest(imageID string) string {
+	if _, digest, found := strings.Cut(imageID, "@"); found {
+		return digest
+	}
+	// Some runtimes report the bare image ID of locally built images.
+	return imageID
+}
+
+// printImageSummary prints every image with the number of containers using it.
+func printImageSummary(images []ImageInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+
+	counts := map[string]int{}
+	var names []string
+	for _, img := range images {
+		if counts[img.Image] == 0 {
+			names = append(names, img.Image)
+		}
+		counts[img.Image]++
+	}
+	sort.Strings(names)
+
+	fmt.Println()
+	headerColor.Printf("%-80s %-10s\n", "IMAGE", "CONTAINERS")
+	line := strings.Repeat("-", 92)
+	lineColor.Println(line)
+	for _, name := range names {
+		fmt.Printf("%-80s %-10d\n", name, counts[name])
+	}
+	fmt.Println()
+}
+
+// printImageDigestTable prints the running digest of every container and warns about
+// images whose replicas run different digests under the same tag.
+func printImageDigestTable(images []ImageInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	warnColor := color.New(color.FgYellow, color.Bold)
+
+	digestsPerImage := map[string]map[string]bool{}
+	for _, img := range images {
+		if img.Digest == "<not running>" {
+			continue
+		}
+		if digestsPerImage[img.Image] == nil {
+			digestsPerImage[img.Image] = map[string]bool{}
+		}
+		digestsPerImage[img.Image][img.Digest] = true
+	}
+
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Image != images[j].Image {
+			return images[i].Image < images[j].Image
+		}
+		return images[i].Pod < images[j].Pod
+	})
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-20s %-45s %-71s\n", "POD", "NAMESPACE", "CONTAINER", "IMAGE", "RUNNING DIGEST")
+	line := strings.Repeat("-", 190)
+	lineColor.Println(line)
+	for _, img := range images {
+		fmt.Printf("%-35s %-15s %-20s %-45s ", img.Pod, img.Namespace, img.Container, img.Image)
+		if len(digestsPerImage[img.Image]) > 1 {
+			warnColor.Printf("%-71s\n", img.Digest)
+		} else {
+			fmt.Printf("%-71s\n", img.Digest)
+		}
+	}
+	fmt.Println()
+
+	var mixed []string
+	for image, digests := range digestsPerImage {
+		if len(digests) > 1 {
+			mixed = append(mixed, fmt.Sprintf("%s is running as %d different digests", image, len(digests)))
+		}
+	}
+	sort.Strings(mixed)
+	for _, m := range mixed {
+		warnColor.Println(m)
+	}
+	if len(mixed) > 0 {
+		fmt.Println()
+	}
+}
@@ -1,30 +1,105 @@
 package cmd
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
 )
 
 // PodInfo holds the essential Pod data we want to display.
 type PodInfo struct {
-	Name      string
-	Namespace string
-	IP        string
-	NodeName  string
-	NodeIP    string
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	IP        string            `json:"ip"`
+	NodeName  string            `json:"nodeName"`
+	NodeIP    string            `json:"nodeIP"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Annotations are only used by the --annotation/--has-annotation filters.
+	Annotations map[string]string `json:"-"`
+	// Owner is the top-level controlling owner as "Kind/Name", e.g. "Deployment/web" or
+	// "Rollout/web", empty for bare pods.
+	Owner string `json:"owner,omitempty"`
+	// ManagedBy is the Flux Kustomization that applied the owner, if any.
+	ManagedBy string `json:"managedBy,omitempty"`
+	// Init is the init container progress shown in the wide output.
+	Init string `json:"init"`
+	// Mesh is the service mesh whose sidecar runs in the pod, empty if none.
+	Mesh string `json:"mesh,omitempty"`
+	// PriorityClass and Priority come from the pod spec; shown in the wide output.
+	PriorityClass string `json:"priorityClass,omitempty"`
+	Priority      int32  `json:"priority"`
+
+	// controller is the pod's direct controller reference, the start of the owner chain.
+	controller *metav1.OwnerReference
 }
 
-// namespaceFlag holds the namespace requested by the user via -n/--namespace
-var namespaceFlag string
+// namespaceFlag holds the namespaces requested by the user via -n/--namespace
+var namespaceFlag []string
+
+// allNamespacesFlag is set by -A/--all-namespaces to search every namespace.
+var allNamespacesFlag bool
+
+// ipConcurrency is the number of namespaces whose pods are listed in parallel.
+var ipConcurrency int
+
+// outputFormat is the -o/--output format; empty prints the colored table.
+var outputFormat string
 
-// configFlags is used to handle kubeconfig-based flags.
+// hasSidecarFlag and noSidecarFlag keep only pods with, or without, a mesh sidecar.
+var hasSidecarFlag, noSidecarFlag bool
+
+// priorityClassFlag keeps only pods of the given priorityClassName.
+var priorityClassFlag string
+
+// outputFile is the --output-file path the results are written to instead of stdout.
+var outputFile string
+
+// outputFileFormats maps --output-file extensions to the -o format used when -o isn't given.
+var outputFileFormats = map[string]string{
+	".csv":     "csv",
+	".json":    "json",
+	".dot":     "dot",
+	".gv":      "dot",
+	".mmd":     "mermaid",
+	".mermaid": "mermaid",
+	".txt":     "wide",
+	".md":      "markdown",
+}
+
+// annotationFlag and hasAnnotationFlag keep only pods with the given key=value
+// annotations, or with the given annotation keys. Repeated flags must all match.
+var annotationFlag, hasAnnotationFlag []string
+
+// annotationFilter is annotationFlag parsed into key/value pairs.
+var annotationFilter map[string]string
+
+// maxWidthFlag truncates table cells wider than it; 0 keeps them whole.
+var maxWidthFlag int
+
+// copyField is the field of the matched pods that --copy puts onto the clipboard.
+var copyField string
+
+// namespacePrefixFlag prefixes every -o name line with "-n <namespace>".
+var namespacePrefixFlag bool
+
+// configFlags is used to handle kubeconfig-based flags. They are registered on
+// RootCmd in client.go.
 var configFlags = genericclioptions.NewConfigFlags(true)
 
 // ipCmd is the main Cobra command for listing Pods by partial name match.
@@ -40,11 +115,33 @@ var ipCmd = &cobra.Command{
 // Add the namespace flag to ipCmd right here.
 func init() {
 	// This registers the -n/--namespace flag with our ipCmd.
-	ipCmd.Flags().StringVarP(&namespaceFlag, "namespace", "n", "",
-		"Namespace to filter pods. Searches all namespaces if omitted.")
+	addNamespaceFlag(ipCmd, "pods")
+	ipCmd.Flags().IntVar(&ipConcurrency, "concurrency", 8,
+		"Number of namespaces to list pods from in parallel.")
+	ipCmd.Flags().StringVarP(&outputFormat, "output", "o", "",
+		"Output format. One of: (empty for table), wide, name, csv, json, markdown, dot, mermaid, go-template=..., custom-columns=..., template:<name from the config file>.")
+	ipCmd.Flags().StringVar(&outputFile, "output-file", "",
+		"Write the results to this file without colors. The format follows the extension (.csv, .json, .md, .dot, .mmd, .txt) unless -o is given.")
+	ipCmd.Flags().BoolVar(&hasSidecarFlag, "has-sidecar", false,
+		"Only show pods running a service mesh sidecar (istio-proxy, linkerd-proxy, envoy).")
+	ipCmd.Flags().BoolVar(&noSidecarFlag, "no-sidecar", false,
+		"Only show pods without a service mesh sidecar.")
+	ipCmd.Flags().StringVar(&priorityClassFlag, "priority-class", "",
+		"Only show pods with this priorityClassName.")
+	ipCmd.Flags().StringArrayVar(&annotationFlag, "annotation", nil,
+		"Only show pods with this annotation, as key=value. Repeat the flag to require several.")
+	ipCmd.Flags().StringArrayVar(&hasAnnotationFlag, "has-annotation", nil,
+		"Only show pods that have this annotation key, whatever its value. Repeat the flag to require several.")
+	ipCmd.Flags().IntVar(&maxWidthFlag, "max-width", 0,
+		"Truncate table cells longer than this many characters with an ellipsis. 0 means no limit.")
+	ipCmd.Flags().StringVar(&copyField, "copy", "",
+		"Copy a field of the matched pods to the clipboard, one per line. One of: ip, name, node. A bare --copy copies the pod IPs.")
+	ipCmd.Flags().Lookup("copy").NoOptDefVal = "ip"
+	ipCmd.Flags().BoolVar(&namespacePrefixFlag, "namespace-prefix", false,
+		"With -o name, prefix every line with \"-n <namespace>\" for use with xargs -L1 kubectl across namespaces.")
 }
 
-// runFunc returns a function that searches for pods (in one or all namespaces)
+// runFunc returns a function that searches for pods (in the requested or all namespaces)
 // and filters them by the provided SEARCH_PATTERN.
 func runFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
@@ -53,60 +150,349 @@ func runFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command
 		}
 		searchTerm := args[0]
 
-		// Retrieve the namespace from kubeconfig (for informational printing only)
-		// clientCfg := configFlags.ToRawKubeConfigLoader()
-		// kubeconfigNamespace, _, err := clientCfg.Namespace()
-		// if err != nil {
-		// 	return fmt.Errorf("failed to determine namespace from kubeconfig: %w", err)
-		// }
-
-		// Decide if we use the namespaceFlag or all namespaces
-		var rb *resource.Builder
-		if namespaceFlag != "" {
-			rb = resource.NewBuilder(configFlags).
-				Unstructured().
-				ResourceTypeOrNameArgs(true, "pods").
-				NamespaceParam(namespaceFlag). // specific namespace
-				ContinueOnError().
-				Flatten()
-		} else {
-			rb = resource.NewBuilder(configFlags).
-				Unstructured().
-				ResourceTypeOrNameArgs(true, "pods").
-				AllNamespaces(true). // all namespaces
-				ContinueOnError().
-				Flatten()
+		if outputFile != "" && !cmd.Flags().Changed("output") {
+			outputFormat = outputFileFormats[strings.ToLower(filepath.Ext(outputFile))]
+		}
+		if name, ok := strings.CutPrefix(outputFormat, "template:"); ok {
+			spec, err := namedTemplate(name)
+			if err != nil {
+				return err
+			}
+			outputFormat = spec
+		}
+		switch {
+		case isTemplateFormat(outputFormat):
+		case outputFormat == "", outputFormat == "wide", outputFormat == "name", outputFormat == "csv",
+			outputFormat == "json", outputFormat == "markdown", outputFormat == "dot", outputFormat == "mermaid":
+		default:
+			return fmt.Errorf("unknown output format %q, must be one of: wide, name, csv, json, markdown, dot, mermaid, go-template=..., custom-columns=..., template:<name>", outputFormat)
+		}
+		switch copyField {
+		case "", "ip", "name", "node":
+		default:
+			return fmt.Errorf("unknown --copy field %q, must be one of: ip, name, node", copyField)
+		}
+		if maxWidthFlag < 0 {
+			return fmt.Errorf("--max-width can't be negative")
+		}
+		if namespacePrefixFlag && outputFormat != "name" {
+			return fmt.Errorf("--namespace-prefix can only be used with -o name")
+		}
+		annotationFilter = map[string]string{}
+		for _, a := range annotationFlag {
+			key, value, found := strings.Cut(a, "=")
+			if !found || key == "" {
+				return fmt.Errorf("invalid --annotation %q, expected key=value", a)
+			}
+			annotationFilter[key] = value
+		}
+		if hasSidecarFlag && noSidecarFlag {
+			return fmt.Errorf("--has-sidecar and --no-sidecar can't be used together")
+		}
+
+		// Falls back to the kubeconfig namespace when neither -n nor -A is given.
+		namespaces, err := targetNamespaces(configFlags)
+		if err != nil {
+			return err
+		}
+
+		// For cluster-wide searches list the namespaces first, so the pod lists can
+		// be spread over the worker pool instead of running as one huge request.
+		if len(namespaces) == 1 && namespaces[0] == metav1.NamespaceAll {
+			namespaces, err = listNamespaceNames(cmd.Context(), configFlags)
+			if isForbidden(err) {
+				return fmt.Errorf("%w\nyou are not allowed to list namespaces; use -n to search the namespaces you have access to", err)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// Show a spinner on stderr while big clusters are being listed.
+		prog := newProgress()
+		prog.start()
+
+		workers := ipConcurrency
+		if workers < 1 {
+			workers = 1
 		}
 
 		var matchingPods []PodInfo
+		// Errors are collected per namespace so one inaccessible namespace doesn't
+		// hide the matches from all the others.
+		var forbidden []string
+		var failures []error
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		jobs := make(chan string)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ns := range jobs {
+					pods, listErr := listMatchingPods(configFlags, ns, searchTerm, prog)
+					mu.Lock()
+					switch {
+					case isForbidden(listErr):
+						forbidden = append(forbidden, ns)
+					case listErr != nil:
+						failures = append(failures, &namespaceError{Namespace: ns, Err: fmt.Errorf("namespace %s: %w", ns, listErr)})
+					}
+					matchingPods = append(matchingPods, pods...)
+					mu.Unlock()
+				}
+			}()
+		}
+		for _, ns := range namespaces {
+			jobs <- ns
+		}
+		close(jobs)
+		wg.Wait()
+		prog.stop()
 
-		err := rb.Do().Visit(func(info *resource.Info, visitErr error) error {
-			if visitErr != nil {
-				return visitErr
+		// Nothing was accessible at all: report the failure instead of an empty result.
+		if len(forbidden)+len(failures) == len(namespaces) && len(namespaces) > 0 {
+			if len(failures) > 0 {
+				return fmt.Errorf("failed to retrieve pods: %w", utilerrors.NewAggregate(failures))
 			}
-			podInfo, convertErr := convertObjectToPodInfo(info.Object)
-			if convertErr != nil {
-				// Skip objects we can't convert
-				return nil
+			return fmt.Errorf("failed to retrieve pods: forbidden in all %d namespace(s)", len(forbidden))
+		}
+
+		historyMatches = len(matchingPods)
+		if len(matchingPods) == 0 {
+			// Keep stdout empty for -o name, it is meant to be piped into kubectl.
+			if outputFormat == "name" {
+				fmt.Fprintf(os.Stderr, "No pods found matching the pattern: %s\n", searchTerm)
+			} else {
+				fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
 			}
-			// If the pod name contains the search term, add it to the list.
-			if strings.Contains(strings.ToLower(podInfo.Name), strings.ToLower(searchTerm)) {
-				matchingPods = append(matchingPods, podInfo)
+		} else {
+			// Workers finish in any order; keep the output stable.
+			sort.Slice(matchingPods, func(i, j int) bool {
+				if matchingPods[i].Namespace != matchingPods[j].Namespace {
+					return matchingPods[i].Namespace < matchingPods[j].Namespace
+				}
+				return matchingPods[i].Name < matchingPods[j].Name
+			})
+
+			// -o name is piped into kubectl and doesn't show owners; skip the lookups.
+			if outputFormat != "name" {
+				if err := resolvePodOwners(cmd.Context(), configFlags, matchingPods); err != nil {
+					return err
+				}
 			}
-			return nil
-		})
+			if err := printPods(cmd, configFlags, matchingPods); err != nil {
+				return err
+			}
+			if copyField != "" {
+				if err := copyPodField(matchingPods); err != nil {
+					return err
+				}
+			}
+		}
+
+		printSkippedNamespaces(forbidden)
+		if len(failures) > 0 {
+			return fmt.Errorf("failed to retrieve pods: %w", utilerrors.NewAggregate(failures))
+		}
+		return nil
+	}
+}
+
+// printPods prints the matching pods in the requested -o format, to stdout or --output-file.
+func printPods(cmd *cobra.Command, configFlags *genericclioptions.ConfigFlags, pods []PodInfo) error {
+	var out io.Writer = color.Output
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve pods: %w", err)
+			return fmt.Errorf("failed to create output file: %w", err)
 		}
+		defer f.Close()
+		// Escape codes would end up in the file as garbage. The shell runs more commands
+		// in this process, so the setting is restored afterwards.
+		noColor := color.NoColor
+		color.NoColor = true
+		defer func() { color.NoColor = noColor }()
+		out = f
+	}
 
-		if len(matchingPods) == 0 {
-			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
-			return nil
+	switch {
+	case isTemplateFormat(outputFormat):
+		if err := printTemplate(out, outputFormat, pods); err != nil {
+			return err
+		}
+	case outputFormat == "dot", outputFormat == "mermaid":
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		services, err := servicesSelectingPods(cmd.Context(), clientset, pods)
+		if err != nil {
+			return err
+		}
+		graph := buildPodGraph(pods, services)
+		if outputFormat == "dot" {
+			printDotGraph(out, graph)
+		} else {
+			printMermaidGraph(out, graph)
 		}
+	case outputFormat == "name":
+		printPodNames(out, pods)
+	case outputFormat == "csv":
+		if err := printPodsCSV(out, pods); err != nil {
+			return err
+		}
+	case outputFormat == "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(pods); err != nil {
+			return fmt.Errorf("failed to write json: %w", err)
+		}
+	case outputFormat == "markdown":
+		podTable(pods, false).printMarkdown(out)
+	default:
+		podTable(pods, outputFormat == "wide").print(out)
+	}
+
+	if outputFile != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d pod(s) to %s\n", len(pods), outputFile)
+	}
+	return nil
+}
 
-		printColoredTable(matchingPods)
+// printPodsCSV writes the pods with all wide columns as CSV, e.g. for spreadsheets.
+func printPodsCSV(out io.Writer, pods []PodInfo) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"name", "namespace", "pod_ip", "node_name", "node_ip", "owner", "mesh", "priority_class", "priority", "init"})
+	for _, p := range pods {
+		w.Write([]string{p.Name, p.Namespace, p.IP, p.NodeName, p.NodeIP, p.Owner, p.Mesh, p.PriorityClass, fmt.Sprintf("%d", p.Priority), p.Init})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// copyPodField puts the --copy field of every pod onto the clipboard, skipping empty
+// values such as the IP of a pod that isn't running yet.
+func copyPodField(pods []PodInfo) error {
+	var values []string
+	for _, p := range pods {
+		value := p.IP
+		switch copyField {
+		case "name":
+			value = p.Name
+		case "node":
+			value = p.NodeName
+		}
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Nothing copied, no matched pod has a %s yet\n", copyField)
 		return nil
 	}
+	if err := copyToClipboard(strings.Join(values, "\n")); err != nil {
+		return err
+	}
+	color.New(color.FgGreen).Fprintf(os.Stderr, "Copied %d %s value(s) to the clipboard\n", len(values), copyField)
+	return nil
+}
+
+// printPodNames prints kubectl's resource-name format, one "pod/<name>" per line, without colors.
+func printPodNames(out io.Writer, pods []PodInfo) {
+	for _, p := range pods {
+		if namespacePrefixFlag {
+			fmt.Fprintf(out, "-n %s pod/%s\n", p.Namespace, p.Name)
+		} else {
+			fmt.Fprintf(out, "pod/%s\n", p.Name)
+		}
+	}
+}
+
+// matchesAnnotationFilter applies --annotation and --has-annotation.
+func matchesAnnotationFilter(p PodInfo) bool {
+	for key, value := range annotationFilter {
+		if v, ok := p.Annotations[key]; !ok || v != value {
+			return false
+		}
+	}
+	for _, key := range hasAnnotationFlag {
+		if _, ok := p.Annotations[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSidecarFilter applies --has-sidecar/--no-sidecar.
+func matchesSidecarFilter(p PodInfo) bool {
+	switch {
+	case hasSidecarFlag:
+		return p.Mesh != ""
+	case noSidecarFlag:
+		return p.Mesh == ""
+	}
+	return true
+}
+
+// printSkippedNamespaces reports the namespaces that couldn't be searched because
+// the user isn't allowed to list pods there.
+func printSkippedNamespaces(namespaces []string) {
+	if len(namespaces) == 0 {
+		return
+	}
+	sort.Strings(namespaces)
+	color.New(color.FgYellow).Fprintf(os.Stderr, "Skipped %d namespace(s) due to authorization failures: %s\n",
+		len(namespaces), strings.Join(namespaces, ", "))
+}
+
+// listMatchingPods lists the pods of a single namespace and returns those whose name
+// contains searchTerm.
+func listMatchingPods(configFlags *genericclioptions.ConfigFlags, namespace, searchTerm string, prog *progress) ([]PodInfo, error) {
+	rb := resource.NewBuilder(configFlags).
+		Unstructured().
+		ResourceTypeOrNameArgs(true, "pods").
+		NamespaceParam(namespace).
+		RequestChunksOf(500). // page through big lists so progress keeps moving
+		ContinueOnError().
+		Flatten()
+
+	var matchingPods []PodInfo
+	consider := func(obj runtime.Object) {
+		podInfo, convertErr := convertObjectToPodInfo(obj)
+		if convertErr != nil {
+			// Skip objects we can't convert
+			return
+		}
+		// If the pod name contains the search term, add it to the list.
+		matched := matchesPattern(podInfo.Name, searchTerm) && matchesSidecarFilter(podInfo) &&
+			(priorityClassFlag == "" || podInfo.PriorityClass == priorityClassFlag) &&
+			matchesAnnotationFilter(podInfo)
+		if matched {
+			matchingPods = append(matchingPods, podInfo)
+		}
+		prog.seen(podInfo.Namespace, matched)
+	}
+
+	// A running daemon answers from its cache without listing the cluster.
+	if cached, ok := daemonList[corev1.Pod](configFlags, "pods", namespace); ok {
+		for i := range cached {
+			consider(&cached[i])
+		}
+		return matchingPods, nil
+	}
+
+	err := rb.Do().Visit(func(info *resource.Info, visitErr error) error {
+		if visitErr != nil {
+			return visitErr
+		}
+		consider(info.Object)
+		return nil
+	})
+	return matchingPods, err
 }
 
 // convertObjectToPodInfo attempts to convert the provided runtime.Object to PodInfo.
@@ -135,41 +521,73 @@ func convertObjectToPodInfo(obj runtime.Object) (PodInfo, error) {
 	// Pod IP
 	podIP, _ := status["podIP"].(string)
 
-	// Node Name
-	nodeNameRaw := spec["nodeName"]
-	nodeName := nodeNameRaw.(string)
+	// Node Name (empty while the pod is not scheduled)
+	nodeName, _ := spec["nodeName"].(string)
 
 	// Node IP
-	hostIPRaw := status["hostIP"]
-	hostIP := hostIPRaw.(string)
+	hostIP, _ := status["hostIP"].(string)
+
+	// Init container progress, mesh sidecar and priority, only shown in the wide output
+	initProgress := "-"
+	mesh := ""
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, &pod); err == nil {
+		initProgress = initColumn(&pod)
+		mesh = podMesh(&pod)
+	}
+
+	// Controlling owner, e.g. the ReplicaSet of a Deployment pod; resolvePodOwners
+	// follows it up to the workload unless the output is -o name.
+	owner := ""
+	controller := metav1.GetControllerOf(unstructuredObj)
+	if controller != nil {
+		owner = controller.Kind + "/" + controller.Name
+	}
 
 	return PodInfo{
-		Name:      podName,
-		Namespace: podNamespace,
-		IP:        podIP,
-		NodeName:  nodeName,
-		NodeIP:    hostIP,
+		Name:          podName,
+		Namespace:     podNamespace,
+		IP:            podIP,
+		NodeName:      nodeName,
+		NodeIP:        hostIP,
+		Labels:        unstructuredObj.GetLabels(),
+		Annotations:   unstructuredObj.GetAnnotations(),
+		Owner:         owner,
+		controller:    controller,
+		Init:          initProgress,
+		Mesh:          mesh,
+		PriorityClass: pod.Spec.PriorityClassName,
+		Priority:      podPriority(&pod),
 	}, nil
 }
 
-// printColoredTable prints the table of matching pods using color for headers and lines.
-func printColoredTable(pods []PodInfo) {
-	// Prepare colored objects from github.com/fatih/color
-	headerColor := color.New(color.FgCyan, color.Bold)
-	lineColor := color.New(color.FgCyan)
-
-	// Print the header line.
-	fmt.Println()
-	// Print the header with colors.
-	headerColor.Printf("%-30s %-20s %-20s %-30s %-20s\n", "NAME", "NAMESPACE", "POD IP", "NODE NAME", "NODE IP")
-
-	// Print a separator line in color.
-	line := strings.Repeat("-", 120)
-	lineColor.Println(line)
+// podTable builds the table of matching pods, printed in color or as Markdown.
+// Both show the OWNER; the wide table adds the MESH, PRIORITY and INIT columns.
+func podTable(pods []PodInfo, wide bool) *table {
+	t := newTable("NAME", "NAMESPACE", "POD IP", "NODE NAME", "NODE IP", "OWNER")
+	if wide {
+		t = newTable("NAME", "NAMESPACE", "POD IP", "NODE NAME", "NODE IP", "OWNER", "MESH", "PRIORITY", "INIT")
+	}
+	t.maxWidth = maxWidthFlag
 
-	// Print each pod line in default color (you could also choose different colors if you want).
 	for _, p := range pods {
-		fmt.Printf("%-30s %-20s %-20s %-30s %-20s\n", p.Name, p.Namespace, p.IP, p.NodeName, p.NodeIP)
+		owner := p.Owner
+		if owner == "" {
+			owner = "-"
+		}
+		if wide {
+			mesh := p.Mesh
+			if mesh == "" {
+				mesh = "-"
+			}
+			priority := fmt.Sprintf("%d", p.Priority)
+			if p.PriorityClass != "" {
+				priority = fmt.Sprintf("%s (%d)", p.PriorityClass, p.Priority)
+			}
+			t.addRow(p.Name, p.Namespace, p.IP, p.NodeName, p.NodeIP, owner, mesh, priority, p.Init)
+		} else {
+			t.addRow(p.Name, p.Namespace, p.IP, p.NodeName, p.NodeIP, owner)
+		}
 	}
-	fmt.Println()
+	return t
 }
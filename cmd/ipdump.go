@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ipdumpFormats are the formats --format accepts.
+var ipdumpFormats = []string{"hosts", "nginx-upstream", "haproxy"}
+
+// ipdump command flags.
+var (
+	ipdumpFormat          string
+	ipdumpPort            string
+	ipdumpName            string
+	ipdumpFile            string
+	ipdumpWatch           bool
+	ipdumpIncludeNotReady bool
+)
+
+// invalidUpstreamChars are replaced in the upstream and backend names nginx and HAProxy accept.
+var invalidUpstreamChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ipdumpCmd writes the IPs of the matching pods in formats load balancers read.
+var ipdumpCmd = &cobra.Command{
+	Use:   "ipdump [SEARCH_PATTERN]",
+	Short: "Write the IPs of pods containing [SEARCH_PATTERN] as an /etc/hosts file, nginx upstream or HAProxy backend, optionally rewriting it on every change.",
+	RunE:  runIPDumpFunc(configFlags),
+	// With --watch the output is rewritten until interrupted.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(ipdumpCmd, "pods")
+	ipdumpCmd.Flags().StringVar(&ipdumpFormat, "format", "hosts",
+		"Output format: hosts, nginx-upstream, haproxy.")
+	ipdumpCmd.Flags().StringVar(&ipdumpPort, "port", "",
+		"Port number or container port name of the nginx and HAProxy servers. Defaults to each pod's first container port.")
+	ipdumpCmd.Flags().StringVar(&ipdumpName, "name", "",
+		"Name of the nginx upstream or HAProxy backend. Defaults to the search pattern.")
+	ipdumpCmd.Flags().StringVarP(&ipdumpFile, "output-file", "f", "",
+		"Write to this file instead of stdout. It is replaced atomically, so readers never see half a file.")
+	ipdumpCmd.Flags().BoolVarP(&ipdumpWatch, "watch", "w", false,
+		"Keep running and rewrite the output whenever the set of IPs changes.")
+	ipdumpCmd.Flags().BoolVar(&ipdumpIncludeNotReady, "include-not-ready", false,
+		"Also include pods that aren't ready. Terminating pods are always left out.")
+}
+
+// runIPDumpFunc returns a function that writes the IPs of the matching pods once, or on
+// every change with --watch.
+func runIPDumpFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+		valid := false
+		for _, f := range ipdumpFormats {
+			valid = valid || f == ipdumpFormat
+		}
+		if !valid {
+			return fmt.Errorf("unknown --format %q, must be one of: hosts, nginx-upstream, haproxy", ipdumpFormat)
+		}
+		name := ipdumpName
+		if name == "" {
+			name = searchTerm
+		}
+		name = invalidUpstreamChars.ReplaceAllString(name, "-")
+		if name == "" || name == "-" {
+			name = "pods"
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		if !ipdumpWatch {
+			pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+			if err != nil {
+				return err
+			}
+			targets := ipdumpTargets(pods)
+			historyMatches = len(targets)
+			data, err := renderIPDump(targets, name)
+			if err != nil {
+				return err
+			}
+			if err := writeIPDump(data); err != nil {
+				return err
+			}
+			if ipdumpFile != "" {
+				fmt.Fprintf(os.Stderr, "Wrote %d pod IP(s) to %s\n", len(targets), ipdumpFile)
+			}
+			return nil
+		}
+
+		namespaces, err := targetNamespaces(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		// Events only mark the output stale; it is rendered from the caches, so a burst of
+		// changes, e.g. a rollout, doesn't write a file per pod.
+		changed := make(chan struct{}, 1)
+		notify := func(interface{}) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+		handler := cache.ResourceEventHandlerFuncs{
+			AddFunc:    notify,
+			UpdateFunc: func(_, obj interface{}) { notify(obj) },
+			DeleteFunc: notify,
+		}
+
+		var stores []cache.Store
+		var synced []cache.InformerSynced
+		for _, ns := range namespaces {
+			factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(ns))
+			informer := factory.Core().V1().Pods().Informer()
+			if _, err := informer.AddEventHandler(handler); err != nil {
+				return fmt.Errorf("failed to watch pods: %w", err)
+			}
+			stores = append(stores, informer.GetStore())
+			synced = append(synced, informer.HasSynced)
+			factory.Start(ctx.Done())
+		}
+		if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+			return fmt.Errorf("failed to sync the pod cache")
+		}
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Watching pods matching %q (Ctrl+C to stop)...\n", searchTerm)
+
+		var last []byte
+		debounce := time.NewTimer(0)
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-changed:
+				debounce.Reset(time.Second)
+				continue
+			case <-debounce.C:
+			}
+
+			var pods []corev1.Pod
+			for _, store := range stores {
+				for _, obj := range store.List() {
+					if pod, ok := obj.(*corev1.Pod); ok && matchesPattern(pod.Name, searchTerm) {
+						pods = append(pods, *pod)
+					}
+				}
+			}
+			targets := ipdumpTargets(pods)
+			historyMatches = len(targets)
+			data, err := renderIPDump(targets, name)
+			if err != nil {
+				// The output stays as it is until the next change rather than ending the watch.
+				color.New(color.FgYellow).Fprintf(os.Stderr, "%v\n", err)
+				continue
+			}
+			if bytes.Equal(data, last) {
+				continue
+			}
+			if err := writeIPDump(data); err != nil {
+				return err
+			}
+			last = data
+			if ipdumpFile != "" {
+				fmt.Fprintf(os.Stderr, "%s wrote %d pod IP(s) to %s\n", time.Now().Format("15:04:05"), len(targets), ipdumpFile)
+			}
+		}
+	}
+}
+
+// ipdumpTargets returns the pods that should receive traffic, sorted by namespace and name:
+// pods with an IP that aren't terminating and, unless --include-not-ready, are ready.
+func ipdumpTargets(pods []corev1.Pod) []corev1.Pod {
+	var targets []corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if !ipdumpIncludeNotReady && !isPodReady(&pod) {
+			continue
+		}
+		targets = append(targets, pod)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Namespace != targets[j].Namespace {
+			return targets[i].Namespace < targets[j].Namespace
+		}
+		return targets[i].Name < targets[j].Name
+	})
+	return targets
+}
+
+// ipdumpPodPort resolves --port for pod: a number, the name of a container port, or the
+// first container port when --port is empty.
+func ipdumpPodPort(pod *corev1.Pod) (int32, error) {
+	if n, err := strconv.ParseInt(ipdumpPort, 10, 32); err == nil {
+		return int32(n), nil
+	}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if ipdumpPort == "" || p.Name == ipdumpPort {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	if ipdumpPort == "" {
+		return 0, fmt.Errorf("pod %s/%s declares no container port, please set --port", pod.Namespace, pod.Name)
+	}
+	return 0, fmt.Errorf("pod %s/%s has no container port named %s", pod.Namespace, pod.Name, ipdumpPort)
+}
+
+// renderIPDump renders the pods in --format. The output doesn't depend on the time, so
+// unchanged pods give an unchanged file.
+func renderIPDump(pods []corev1.Pod, name string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by kubectl helper ipdump, %d pod(s). Don't edit, it is overwritten.\n", len(pods))
+	switch ipdumpFormat {
+	case "hosts":
+		for _, pod := range pods {
+			fmt.Fprintf(&b, "%s\t%s %s.%s\n", pod.Status.PodIP, pod.Name, pod.Name, pod.Namespace)
+		}
+
+	case "nginx-upstream":
+		fmt.Fprintf(&b, "upstream %s {\n", name)
+		for i := range pods {
+			port, err := ipdumpPodPort(&pods[i])
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "    server %s; # %s/%s\n", hostPort(pods[i].Status.PodIP, port), pods[i].Namespace, pods[i].Name)
+		}
+		// nginx refuses to load an upstream without servers.
+		if len(pods) == 0 {
+			b.WriteString("    server 127.0.0.1:1 down; # no ready pods\n")
+		}
+		b.WriteString("}\n")
+
+	case "haproxy":
+		fmt.Fprintf(&b, "backend %s\n", name)
+		b.WriteString("    balance roundrobin\n")
+		for i := range pods {
+			port, err := ipdumpPodPort(&pods[i])
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "    server %s.%s %s check\n", pods[i].Name, pods[i].Namespace, hostPort(pods[i].Status.PodIP, port))
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// hostPort joins an IP and port, bracketing IPv6 addresses.
+func hostPort(ip string, port int32) string {
+	return net.JoinHostPort(ip, strconv.Itoa(int(port)))
+}
+
+// writeIPDump writes data to stdout, or replaces --output-file with it through a temporary
+// file in the same directory and a rename.
+func writeIPDump(data []byte) error {
+	if ipdumpFile == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(ipdumpFile), "."+filepath.Base(ipdumpFile)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", ipdumpFile, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", ipdumpFile, err)
+	}
+	// CreateTemp makes the file private; load balancers often run as another user.
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", ipdumpFile, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ipdumpFile, err)
+	}
+	if err := os.Rename(tmp.Name(), ipdumpFile); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ipdumpFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LeaseInfo holds the leader election state kept in one Lease.
+type LeaseInfo struct {
+	Name      string
+	Namespace string
+	Holder    string
+	// Pod is the pod the holder identity belongs to, empty when none was found.
+	Pod         string
+	Acquired    time.Time
+	Renewed     time.Time
+	Duration    time.Duration
+	Transitions int32
+}
+
+// leaderCmd shows who holds the leases matching a pattern.
+var leaderCmd = &cobra.Command{
+	Use:   "leader [SEARCH_PATTERN]",
+	Short: "Show the holder, acquire time and renew age of Leases containing [SEARCH_PATTERN], and the pod holding them.",
+	RunE:  runLeaderFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(leaderCmd, "leases")
+}
+
+// runLeaderFunc returns a function that prints the holders of the matching leases.
+func runLeaderFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper leader cert-manager\nor:\n  kubectl helper leader -n kube-system controller-manager")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		leases, err := listAcrossNamespaces(configFlags, func(ns string) ([]coordinationv1.Lease, error) {
+			list, err := clientset.CoordinationV1().Leases(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve leases: %w", err)
+		}
+
+		var infos []LeaseInfo
+		podsByNamespace := map[string][]corev1.Pod{}
+		for _, lease := range leases {
+			// Node heartbeats are leases too, but nodehealth covers them.
+			if lease.Namespace == corev1.NamespaceNodeLease || !matchesPattern(lease.Name, searchTerm) {
+				continue
+			}
+			info := LeaseInfo{Name: lease.Name, Namespace: lease.Namespace}
+			if lease.Spec.HolderIdentity != nil {
+				info.Holder = *lease.Spec.HolderIdentity
+			}
+			if lease.Spec.AcquireTime != nil {
+				info.Acquired = lease.Spec.AcquireTime.Time
+			}
+			if lease.Spec.RenewTime != nil {
+				info.Renewed = lease.Spec.RenewTime.Time
+			}
+			if lease.Spec.LeaseDurationSeconds != nil {
+				info.Duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+			}
+			if lease.Spec.LeaseTransitions != nil {
+				info.Transitions = *lease.Spec.LeaseTransitions
+			}
+
+			if info.Holder != "" {
+				pods, ok := podsByNamespace[lease.Namespace]
+				if !ok {
+					pods, err = leaseNamespacePods(ctx, clientset, lease.Namespace)
+					if err != nil {
+						return err
+					}
+					podsByNamespace[lease.Namespace] = pods
+				}
+				info.Pod = holderPod(info.Holder, pods)
+			}
+			infos = append(infos, info)
+		}
+		historyMatches = len(infos)
+		if len(infos) == 0 {
+			fmt.Printf("No leases found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		sort.Slice(infos, func(i, j int) bool {
+			if infos[i].Namespace != infos[j].Namespace {
+				return infos[i].Namespace < infos[j].Namespace
+			}
+			return infos[i].Name < infos[j].Name
+		})
+		printLeaseTable(infos)
+		return nil
+	}
+}
+
+// leaseNamespacePods lists the pods that may hold the leases of a namespace. Without the
+// permission to list them, holders simply aren't mapped to pods.
+func leaseNamespacePods(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+	list, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if isForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+	return list.Items, nil
+}
+
+// holderPod maps a holder identity back to a pod. client-go's leader election uses the
+// hostname, i.e. the pod name, often followed by "_" and a random ID.
+func holderPod(holder string, pods []corev1.Pod) string {
+	for _, pod := range pods {
+		if holder == pod.Name || strings.HasPrefix(holder, pod.Name+"_") {
+			return pod.Name
+		}
+	}
+	return ""
+}
+
+// printLeaseTable prints a row per lease; leases not renewed within their duration are red.
+func printLeaseTable(infos []LeaseInfo) {
+	warnColor := color.New(color.FgYellow)
+	expiredColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("NAME", "NAMESPACE", "HOLDER", "POD", "ACQUIRED", "RENEWED", "TRANSITIONS")
+	for _, l := range infos {
+		holder, pod, acquired, renewed := l.Holder, l.Pod, "-", "-"
+		if holder == "" {
+			holder = "<none>"
+		}
+		if pod == "" {
+			pod = "-"
+		}
+		if !l.Acquired.IsZero() {
+			acquired = duration.HumanDuration(time.Since(l.Acquired)) + " ago"
+		}
+		if !l.Renewed.IsZero() {
+			renewed = duration.HumanDuration(time.Since(l.Renewed)) + " ago"
+		}
+		t.addRow(l.Name, l.Namespace, holder, pod, acquired, renewed, fmt.Sprintf("%d", l.Transitions))
+
+		if l.Holder == "" {
+			t.colorCell(2, warnColor)
+		} else if l.Pod == "" {
+			t.colorCell(3, warnColor)
+		}
+		if l.Duration > 0 && !l.Renewed.IsZero() && time.Since(l.Renewed) > l.Duration {
+			t.colorCell(5, expiredColor)
+		}
+	}
+	t.print(color.Output)
+}
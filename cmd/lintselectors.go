@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// SelectorFinding is one suspicious selector or reference.
+type SelectorFinding struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Problem   string
+	// Hint is the likely intended value, e.g. the pod label a selector almost matches.
+	Hint string
+}
+
+// lintSelectorsCmd cross-checks the selectors and references that silently match nothing.
+var lintSelectorsCmd = &cobra.Command{
+	Use:   "lint-selectors [SEARCH_PATTERN]",
+	Short: "Find Service, Deployment and PDB selectors and HPA targets named like [SEARCH_PATTERN] (all if omitted) that match nothing or overlap.",
+	RunE:  runLintSelectorsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(lintSelectorsCmd, "objects")
+}
+
+// runLintSelectorsFunc returns a function that prints every suspicious selector.
+func runLintSelectorsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, "")
+		if err != nil {
+			return err
+		}
+		services, err := listAcrossNamespaces(configFlags, func(ns string) ([]corev1.Service, error) {
+			list, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve services: %w", err)
+		}
+		deployments, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.Deployment, error) {
+			list, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve deployments: %w", err)
+		}
+		statefulSets, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.StatefulSet, error) {
+			list, err := clientset.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve statefulsets: %w", err)
+		}
+		pdbs, err := listAcrossNamespaces(configFlags, func(ns string) ([]policyv1.PodDisruptionBudget, error) {
+			list, err := clientset.PolicyV1().PodDisruptionBudgets(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve poddisruptionbudgets: %w", err)
+		}
+		hpas, err := listAcrossNamespaces(configFlags, func(ns string) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+			list, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve horizontalpodautoscalers: %w", err)
+		}
+
+		// Finished pods don't receive traffic and aren't protected by PDBs.
+		podsByNamespace := map[string][]corev1.Pod{}
+		for _, pod := range pods {
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+			}
+		}
+
+		var findings []SelectorFinding
+		for _, svc := range services {
+			if len(svc.Spec.Selector) == 0 || !matchesPattern(svc.Name, searchTerm) {
+				continue
+			}
+			if countMatchingPods(labels.SelectorFromSet(svc.Spec.Selector), podsByNamespace[svc.Namespace]) == 0 {
+				findings = append(findings, SelectorFinding{
+					Namespace: svc.Namespace, Kind: "Service", Name: svc.Name,
+					Problem: "selector " + labels.Set(svc.Spec.Selector).String() + " matches no pods",
+					Hint:    selectorHint(svc.Spec.Selector, podsByNamespace[svc.Namespace]),
+				})
+			}
+		}
+
+		for _, d := range deployments {
+			if !matchesPattern(d.Name, searchTerm) {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			// Controllers with overlapping selectors fight over each other's pods.
+			for _, other := range deployments {
+				if other.Namespace == d.Namespace && other.Name != d.Name && selector.Matches(labels.Set(other.Spec.Template.Labels)) {
+					findings = append(findings, SelectorFinding{
+						Namespace: d.Namespace, Kind: "Deployment", Name: d.Name,
+						Problem: "selector " + selector.String() + " also matches the pods of Deployment/" + other.Name,
+						Hint:    "add a label only " + d.Name + "'s pods carry to its selector",
+					})
+				}
+			}
+		}
+
+		for _, pdb := range pdbs {
+			if !matchesPattern(pdb.Name, searchTerm) {
+				continue
+			}
+			if pdb.Spec.Selector == nil {
+				findings = append(findings, SelectorFinding{
+					Namespace: pdb.Namespace, Kind: "PodDisruptionBudget", Name: pdb.Name,
+					Problem: "has no selector, so it protects no pods", Hint: "-",
+				})
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil {
+				continue
+			}
+			if countMatchingPods(selector, podsByNamespace[pdb.Namespace]) == 0 {
+				findings = append(findings, SelectorFinding{
+					Namespace: pdb.Namespace, Kind: "PodDisruptionBudget", Name: pdb.Name,
+					Problem: "selector " + selector.String() + " matches no pods",
+					Hint:    selectorHint(pdb.Spec.Selector.MatchLabels, podsByNamespace[pdb.Namespace]),
+				})
+			}
+		}
+
+		workloads := map[string][]string{}
+		for _, d := range deployments {
+			workloads[d.Namespace+"/Deployment"] = append(workloads[d.Namespace+"/Deployment"], d.Name)
+		}
+		for _, s := range statefulSets {
+			workloads[s.Namespace+"/StatefulSet"] = append(workloads[s.Namespace+"/StatefulSet"], s.Name)
+		}
+		for _, hpa := range hpas {
+			ref := hpa.Spec.ScaleTargetRef
+			// Other kinds, e.g. CRDs, aren't listed here.
+			if !matchesPattern(hpa.Name, searchTerm) || (ref.Kind != "Deployment" && ref.Kind != "StatefulSet") {
+				continue
+			}
+			names := workloads[hpa.Namespace+"/"+ref.Kind]
+			if slices.Contains(names, ref.Name) {
+				continue
+			}
+			hint := "-"
+			if closest := closestName(ref.Name, names); closest != "" {
+				hint = "did you mean " + ref.Kind + "/" + closest + "?"
+			}
+			findings = append(findings, SelectorFinding{
+				Namespace: hpa.Namespace, Kind: "HorizontalPodAutoscaler", Name: hpa.Name,
+				Problem: "target " + ref.Kind + "/" + ref.Name + " doesn't exist",
+				Hint:    hint,
+			})
+		}
+
+		historyMatches = len(findings)
+		if len(findings) == 0 {
+			color.New(color.FgGreen).Printf("No selector mismatches found for objects matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		sort.SliceStable(findings, func(i, j int) bool {
+			if findings[i].Namespace != findings[j].Namespace {
+				return findings[i].Namespace < findings[j].Namespace
+			}
+			return findings[i].Kind < findings[j].Kind
+		})
+		printSelectorFindings(findings)
+		return nil
+	}
+}
+
+// countMatchingPods returns how many pods selector selects.
+func countMatchingPods(selector labels.Selector, pods []corev1.Pod) int {
+	n := 0
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			n++
+		}
+	}
+	return n
+}
+
+// selectorHint finds the pods a selector almost matches, all but one of its labels, and
+// describes the difference, e.g. `pods have app=frontend, not app=frontnd`.
+func selectorHint(selector map[string]string, pods []corev1.Pod) string {
+	if len(selector) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hints := map[string]int{}
+	for _, pod := range pods {
+		var missed []string
+		for _, k := range keys {
+			if v, ok := pod.Labels[k]; !ok || v != selector[k] {
+				missed = append(missed, k)
+			}
+		}
+		if len(missed) != 1 {
+			continue
+		}
+		k := missed[0]
+		if v, ok := pod.Labels[k]; ok {
+			hints[fmt.Sprintf("pods have %s=%s, not %s=%s", k, v, k, selector[k])]++
+			continue
+		}
+		podKeys := make([]string, 0, len(pod.Labels))
+		for pk := range pod.Labels {
+			podKeys = append(podKeys, pk)
+		}
+		if closest := closestName(k, podKeys); closest != "" {
+			hints[fmt.Sprintf("pods have label %s=%s, not %s", closest, pod.Labels[closest], k)]++
+		} else {
+			hints[fmt.Sprintf("pods lack the label %s", k)]++
+		}
+	}
+	best, count := "-", 0
+	for hint, n := range hints {
+		if n > count || (n == count && hint < best) {
+			best, count = hint, n
+		}
+	}
+	return best
+}
+
+// closestName returns the candidate within a small edit distance of name, "" if none is.
+func closestName(name string, candidates []string) string {
+	best, bestDistance := "", max(2, len(name)/4)+1
+	for _, c := range candidates {
+		if d := editDistance(name, c); d > 0 && d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// printSelectorFindings prints a row per finding, with the problem in yellow.
+func printSelectorFindings(findings []SelectorFinding) {
+	warnColor := color.New(color.FgYellow)
+
+	t := newTable("NAMESPACE", "KIND", "NAME", "PROBLEM", "HINT")
+	for _, f := range findings {
+		t.addRow(f.Namespace, f.Kind, f.Name, f.Problem, f.Hint)
+		t.colorCell(3, warnColor)
+	}
+	t.print(color.Output)
+	fmt.Printf("%d suspicious selector(s) or reference(s)\n\n", len(findings))
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// labeledPods returns a pod per label set.
+func labeledPods(sets ...map[string]string) []corev1.Pod {
+	var pods []corev1.Pod
+	for _, set := range sets {
+		pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: set}})
+	}
+	return pods
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"app", "app", 0},
+		{"app", "", 3},
+		{"frontend", "frontnd", 1},
+		{"kitten", "sitting", 3},
+		{"tier", "teir", 2},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{"app", []string{"ap", "tier"}, "ap"},
+		{"app.kubernetes.io/name", []string{"app.kubernetes.io/nmae", "tier"}, "app.kubernetes.io/nmae"},
+		// The name itself isn't a typo of itself.
+		{"app", []string{"app"}, ""},
+		{"app", []string{"version", "component"}, ""},
+		{"tier", []string{"teir", "tie"}, "tie"},
+	}
+	for _, tt := range tests {
+		if got := closestName(tt.name, tt.candidates); got != tt.want {
+			t.Errorf("closestName(%q, %q) = %q, want %q", tt.name, tt.candidates, got, tt.want)
+		}
+	}
+}
+
+func TestSelectorHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector map[string]string
+		pods     []corev1.Pod
+		want     string
+	}{
+		{
+			name: "empty selector",
+			pods: labeledPods(map[string]string{"app": "web"}),
+			want: "-",
+		},
+		{
+			name:     "typo in the value",
+			selector: map[string]string{"app": "frontnd", "tier": "web"},
+			pods:     labeledPods(map[string]string{"app": "frontend", "tier": "web"}),
+			want:     "pods have app=frontend, not app=frontnd",
+		},
+		{
+			name:     "typo in the key",
+			selector: map[string]string{"ap": "web"},
+			pods:     labeledPods(map[string]string{"app": "web"}),
+			want:     "pods have label app=web, not ap",
+		},
+		{
+			name:     "missing label",
+			selector: map[string]string{"app": "web", "release": "stable"},
+			pods:     labeledPods(map[string]string{"app": "web"}),
+			want:     "pods lack the label release",
+		},
+		{
+			name:     "pods off by more than one label",
+			selector: map[string]string{"app": "web", "tier": "front"},
+			pods:     labeledPods(map[string]string{"app": "db", "tier": "back"}),
+			want:     "-",
+		},
+		{
+			name:     "most common hint wins",
+			selector: map[string]string{"app": "web"},
+			pods: labeledPods(
+				map[string]string{"app": "api"},
+				map[string]string{"app": "webapp"},
+				map[string]string{"app": "webapp"},
+			),
+			want: "pods have app=webapp, not app=web",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectorHint(tt.selector, tt.pods); got != tt.want {
+				t.Errorf("selectorHint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountMatchingPods(t *testing.T) {
+	pods := labeledPods(
+		map[string]string{"app": "web", "tier": "front"},
+		map[string]string{"app": "web"},
+		map[string]string{"app": "db"},
+	)
+	tests := []struct {
+		selector map[string]string
+		want     int
+	}{
+		{map[string]string{"app": "web"}, 2},
+		{map[string]string{"app": "web", "tier": "front"}, 1},
+		{map[string]string{"app": "cache"}, 0},
+	}
+	for _, tt := range tests {
+		if got := countMatchingPods(labels.SelectorFromSet(tt.selector), pods); got != tt.want {
+			t.Errorf("countMatchingPods(%v) = %d, want %d", tt.selector, got, tt.want)
+		}
+	}
+}
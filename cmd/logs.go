@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LogMatchInfo holds the grep result of one pod container.
+type LogMatchInfo struct {
+	Pod       string
+	Namespace string
+	Container string
+	Matches   int
+	Err       error
+}
+
+var (
+	// logsGrep is the regular expression log lines are filtered by.
+	logsGrep string
+	// logsContext is the number of lines printed around every match.
+	logsContext int
+	// logsContainer limits the logs to a single container name.
+	logsContainer string
+	// logsTail is the number of lines fetched from the end of each log, -1 for all.
+	logsTail int64
+	// logsSince only returns logs newer than this duration.
+	logsSince time.Duration
+	// logsFollow keeps streaming the logs until interrupted.
+	logsFollow bool
+	// logsTimestamps prefixes every line with the timestamp recorded by the kubelet.
+	logsTimestamps bool
+)
+
+// logsReconnectDelay is how long a followed stream waits before reconnecting after it ended.
+const logsReconnectDelay = 2 * time.Second
+
+// logPrefixColors are the colors pod prefixes cycle through. Red is left out so
+// errors still stand out.
+var logPrefixColors = []*color.Color{
+	color.New(color.FgGreen),
+	color.New(color.FgYellow),
+	color.New(color.FgBlue),
+	color.New(color.FgMagenta),
+	color.New(color.FgCyan),
+	color.New(color.FgHiGreen),
+	color.New(color.FgHiYellow),
+	color.New(color.FgHiBlue),
+	color.New(color.FgHiMagenta),
+	color.New(color.FgHiCyan),
+}
+
+// logsCmd fetches logs from every matching pod and optionally greps them.
+var logsCmd = &cobra.Command{
+	Use:   "logs [SEARCH_PATTERN]",
+	Short: "Fetch logs from all pods containing [SEARCH_PATTERN], optionally filtered by --grep.",
+	RunE:  runLogsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(logsCmd, "pods")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "",
+		"Only print log lines matching this regular expression.")
+	logsCmd.Flags().IntVarP(&logsContext, "grep-context", "C", 0,
+		"Number of lines to print before and after every --grep match.")
+	logsCmd.Flags().StringVarP(&logsContainer, "container", "c", "",
+		"Only fetch logs of this container. All containers if omitted.")
+	logsCmd.Flags().Int64Var(&logsTail, "tail", -1,
+		"Number of recent lines to fetch from each container, -1 for all.")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0,
+		"Only return logs newer than a relative duration like 5s, 2m, or 3h.")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false,
+		"Keep streaming the logs until interrupted.")
+	logsCmd.Flags().BoolVar(&logsTimestamps, "timestamps", false,
+		"Include the timestamp of every log line.")
+}
+
+// logTarget is one container whose logs we stream.
+type logTarget struct {
+	pod       corev1.Pod
+	container string
+}
+
+// runLogsFunc returns a function that streams the logs of the matching pods in parallel.
+func runLogsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper logs nginx --grep ERROR\nor:\n  kubectl helper logs -n dev nginx -f --grep 'timeout|refused'")
+		}
+		searchTerm := args[0]
+
+		var re *regexp.Regexp
+		if logsGrep != "" {
+			var err error
+			if re, err = regexp.Compile(logsGrep); err != nil {
+				return fmt.Errorf("invalid --grep expression: %w", err)
+			}
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		if logsFollow {
+			// Ctrl-C stops the streams but still prints the match summary.
+			var stop context.CancelFunc
+			ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+		}
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+
+		var targets []logTarget
+		for _, pod := range pods {
+			for _, c := range pod.Spec.Containers {
+				if logsContainer == "" || c.Name == logsContainer {
+					targets = append(targets, logTarget{pod: pod, container: c.Name})
+				}
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		results := make([]LogMatchInfo, len(targets))
+		buffers := make([][]string, len(targets))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+
+		for i, t := range targets {
+			wg.Add(1)
+			go func(i int, t logTarget) {
+				defer wg.Done()
+				prefix := logPrefix(t.pod.Name, t.container)
+				// When following, lines are printed as they arrive; otherwise they are
+				// collected so every container's output stays together.
+				emit := func(line string) {
+					if logsFollow {
+						mu.Lock()
+						fmt.Println(prefix + line)
+						mu.Unlock()
+						return
+					}
+					buffers[i] = append(buffers[i], prefix+line)
+				}
+				matches, err := followContainerLogs(ctx, clientset, t, re, emit)
+				results[i] = LogMatchInfo{
+					Pod:       t.pod.Name,
+					Namespace: t.pod.Namespace,
+					Container: t.container,
+					Matches:   matches,
+					Err:       err,
+				}
+			}(i, t)
+		}
+		wg.Wait()
+
+		for _, lines := range buffers {
+			for _, line := range lines {
+				fmt.Println(line)
+			}
+		}
+
+		if re != nil {
+			printLogMatchTable(results)
+		} else {
+			for _, r := range results {
+				if r.Err != nil {
+					color.New(color.FgRed).Fprintf(os.Stderr, "%s/%s: %v\n", r.Pod, r.Container, r.Err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// logPrefix returns the colored "[pod/container] " prefix of a log line. The color is derived
+// from the names rather than the stream order, so a container keeps its color across
+// reconnects and separate invocations.
+func logPrefix(pod, container string) string {
+	h := fnv.New32a()
+	h.Write([]byte(pod + "/" + container))
+	c := logPrefixColors[h.Sum32()%uint32(len(logPrefixColors))]
+	return c.Sprintf("[%s/%s]", pod, container) + " "
+}
+
+// followContainerLogs streams the logs of one container. When following, a stream that ends
+// (container restart, API server timeout) is reopened from the moment it was closed.
+func followContainerLogs(ctx context.Context, clientset kubernetes.Interface, t logTarget, re *regexp.Regexp, emit func(string)) (int, error) {
+	var since *metav1.Time
+	total := 0
+	for {
+		matches, err := streamContainerLogs(ctx, clientset, t, re, since, emit)
+		total += matches
+		if err != nil || !logsFollow || ctx.Err() != nil {
+			return total, err
+		}
+
+		since = &metav1.Time{Time: time.Now()}
+		select {
+		case <-ctx.Done():
+			return total, nil
+		case <-time.After(logsReconnectDelay):
+		}
+	}
+}
+
+// streamContainerLogs reads the logs of one container and hands every line that should be
+// printed to emit. With a regular expression only matches and their context lines are
+// emitted, and groups of lines that aren't adjacent are separated by "--" like grep does.
+func streamContainerLogs(ctx context.Context, clientset kubernetes.Interface, t logTarget, re *regexp.Regexp, since *metav1.Time, emit func(string)) (int, error) {
+	opts := &corev1.PodLogOptions{
+		Container:  t.container,
+		Follow:     logsFollow,
+		Timestamps: logsTimestamps,
+	}
+	if since != nil {
+		// Reconnecting: only pick up what was logged after the previous stream ended.
+		opts.SinceTime = since
+	} else if logsTail >= 0 {
+		opts.TailLines = &logsTail
+	}
+	if since == nil && logsSince > 0 {
+		seconds := int64(logsSince.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+
+	stream, err := clientset.CoreV1().Pods(t.pod.Namespace).GetLogs(t.pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stream logs: %w", err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	matches := 0
+	lineNo := 0
+	lastEmitted := 0 // line number of the last emitted line, 0 if none yet
+	afterLeft := 0
+	var before []string
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		if re == nil {
+			emit(line)
+			continue
+		}
+
+		if re.MatchString(line) {
+			matches++
+			first := lineNo - len(before)
+			if lastEmitted > 0 && first > lastEmitted+1 {
+				emit("--")
+			}
+			for _, b := range before {
+				emit(b)
+			}
+			before = before[:0]
+			emit(line)
+			lastEmitted = lineNo
+			afterLeft = logsContext
+			continue
+		}
+
+		if afterLeft > 0 {
+			emit(line)
+			lastEmitted = lineNo
+			afterLeft--
+			continue
+		}
+
+		if logsContext > 0 {
+			if len(before) == logsContext {
+				before = before[1:]
+			}
+			before = append(before, line)
+		}
+	}
+
+	// A cancelled follow is the normal way to stop, not an error.
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return matches, fmt.Errorf("failed to read logs: %w", err)
+	}
+	return matches, nil
+}
+
+// printLogMatchTable prints the number of --grep matches per pod container.
+func printLogMatchTable(results []LogMatchInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	matchColor := color.New(color.FgYellow, color.Bold)
+	errorColor := color.New(color.FgRed)
+
+	fmt.Println()
+	headerColor.Printf("%-40s %-20s %-25s %-10s\n", "NAME", "NAMESPACE", "CONTAINER", "MATCHES")
+
+	line := strings.Repeat("-", 100)
+	lineColor.Println(line)
+
+	total := 0
+	for _, r := range results {
+		fmt.Printf("%-40s %-20s %-25s ", r.Pod, r.Namespace, r.Container)
+		switch {
+		case r.Err != nil:
+			errorColor.Println(r.Err)
+		case r.Matches > 0:
+			matchColor.Printf("%-10d\n", r.Matches)
+		default:
+			fmt.Printf("%-10d\n", r.Matches)
+		}
+		total += r.Matches
+	}
+	fmt.Println()
+	fmt.Printf("%d match(es) in %d container(s)\n\n", total, len(results))
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// meshSidecars maps well-known sidecar container names to the mesh that injects them.
+var meshSidecars = map[string]string{
+	"istio-proxy":      "istio",
+	"linkerd-proxy":    "linkerd",
+	"envoy":            "envoy",
+	"envoy-sidecar":    "envoy",
+	"consul-dataplane": "consul",
+}
+
+// podMesh returns the service mesh whose sidecar runs in pod, or "" if there is none.
+// Native sidecars (init containers with restartPolicy: Always) are detected as well.
+func podMesh(pod *corev1.Pod) string {
+	var containers []corev1.Container
+	containers = append(containers, pod.Spec.Containers...)
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containers = append(containers, c)
+		}
+	}
+
+	for _, c := range containers {
+		if mesh, ok := meshSidecars[c.Name]; ok {
+			return mesh
+		}
+		// Custom-named Envoy sidecars still run an envoy image.
+		if strings.Contains(c.Image, "/envoy:") || strings.Contains(c.Image, "envoyproxy/") {
+			return "envoy"
+		}
+	}
+	return ""
+}
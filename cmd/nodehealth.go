@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodeHealthInfo holds the health signals of one node.
+type NodeHealthInfo struct {
+	Name string
+	// Ready is the status of the Ready condition: True, False or Unknown.
+	Ready         corev1.ConditionStatus
+	Unschedulable bool
+	// Pressures are the pressure conditions that are True, e.g. MemoryPressure.
+	Pressures []string
+	// Heartbeat is the time since the kubelet last renewed its lease, or reported
+	// its status when there is no lease.
+	Heartbeat time.Duration
+	Events    []corev1.Event
+}
+
+// Kubelets renew their lease every 10s; the node controller gives up after 40s.
+const (
+	heartbeatWarning  = 20 * time.Second
+	heartbeatCritical = 40 * time.Second
+)
+
+// nodehealthEvents is the number of recent events shown per node.
+var nodehealthEvents int
+
+// nodehealthCmd shows the conditions, heartbeat and events of the matching nodes.
+var nodehealthCmd = &cobra.Command{
+	Use:   "nodehealth [SEARCH_PATTERN]",
+	Short: "Show the conditions, kubelet heartbeat and recent events of nodes containing [SEARCH_PATTERN] (all if omitted).",
+	RunE:  runNodeHealthFunc(configFlags),
+}
+
+func init() {
+	nodehealthCmd.Flags().IntVar(&nodehealthEvents, "events", 5, "Number of recent events shown per node, 0 to hide them.")
+}
+
+// runNodeHealthFunc returns a function that prints the health table of the matching nodes.
+func runNodeHealthFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+		// Leases are the real heartbeat; without access to them the status report stands in.
+		leases, err := clientset.CoordinationV1().Leases(corev1.NamespaceNodeLease).List(ctx, metav1.ListOptions{})
+		if err != nil && !isForbidden(err) {
+			return fmt.Errorf("failed to retrieve node leases: %w", err)
+		}
+		renewed := map[string]time.Time{}
+		if leases != nil {
+			for _, lease := range leases.Items {
+				if lease.Spec.RenewTime != nil {
+					renewed[lease.Name] = lease.Spec.RenewTime.Time
+				}
+			}
+		}
+
+		var infos []NodeHealthInfo
+		for _, node := range nodes.Items {
+			if !matchesPattern(node.Name, searchTerm) {
+				continue
+			}
+			info := NodeHealthInfo{Name: node.Name, Ready: corev1.ConditionUnknown, Unschedulable: node.Spec.Unschedulable}
+			var lastReport time.Time
+			for _, cond := range node.Status.Conditions {
+				switch {
+				case cond.Type == corev1.NodeReady:
+					info.Ready = cond.Status
+					lastReport = cond.LastHeartbeatTime.Time
+				case strings.HasSuffix(string(cond.Type), "Pressure") && cond.Status == corev1.ConditionTrue:
+					info.Pressures = append(info.Pressures, string(cond.Type))
+				case cond.Type == corev1.NodeNetworkUnavailable && cond.Status == corev1.ConditionTrue:
+					info.Pressures = append(info.Pressures, string(cond.Type))
+				}
+			}
+			if t, ok := renewed[node.Name]; ok {
+				lastReport = t
+			}
+			if !lastReport.IsZero() {
+				info.Heartbeat = time.Since(lastReport)
+			}
+			if nodehealthEvents > 0 {
+				info.Events, err = nodeEvents(ctx, clientset, node.Name, nodehealthEvents)
+				if err != nil {
+					return err
+				}
+			}
+			infos = append(infos, info)
+		}
+		historyMatches = len(infos)
+		if len(infos) == 0 {
+			fmt.Printf("No nodes found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// Failing nodes first, so they can't scroll out of sight.
+		sort.SliceStable(infos, func(i, j int) bool {
+			return nodeSeverity(infos[i]) > nodeSeverity(infos[j])
+		})
+		printNodeHealthTable(infos)
+		printNodeEvents(infos)
+		return nil
+	}
+}
+
+// nodeEvents returns the newest events recorded for a node, at most limit.
+func nodeEvents(ctx context.Context, clientset kubernetes.Interface, node string, limit int) ([]corev1.Event, error) {
+	events, err := clientset.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "Node"),
+			fields.OneTermEqualSelector("involvedObject.name", node),
+		).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve events: %w", err)
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).After(eventTime(&events.Items[j]))
+	})
+	if len(events.Items) > limit {
+		events.Items = events.Items[:limit]
+	}
+	return events.Items, nil
+}
+
+// nodeSeverity ranks a node: 2 is failing, 1 needs a look, 0 is healthy.
+func nodeSeverity(n NodeHealthInfo) int {
+	switch {
+	case n.Ready != corev1.ConditionTrue, len(n.Pressures) > 0, n.Heartbeat > heartbeatCritical:
+		return 2
+	case n.Unschedulable, n.Heartbeat > heartbeatWarning:
+		return 1
+	}
+	return 0
+}
+
+// printNodeHealthTable prints a row per node; failing signals are red, suspicious ones yellow.
+func printNodeHealthTable(infos []NodeHealthInfo) {
+	okColor := color.New(color.FgGreen)
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("NAME", "STATUS", "PRESSURE", "HEARTBEAT")
+	for _, n := range infos {
+		status := "Ready"
+		switch n.Ready {
+		case corev1.ConditionFalse:
+			status = "NotReady"
+		case corev1.ConditionUnknown:
+			status = "Unknown"
+		}
+		if n.Unschedulable {
+			status += ",SchedulingDisabled"
+		}
+		pressure := "-"
+		if len(n.Pressures) > 0 {
+			pressure = strings.Join(n.Pressures, ",")
+		}
+		heartbeat := "-"
+		if n.Heartbeat > 0 {
+			heartbeat = duration.HumanDuration(n.Heartbeat) + " ago"
+		}
+		t.addRow(n.Name, status, pressure, heartbeat)
+
+		switch {
+		case n.Ready != corev1.ConditionTrue:
+			t.colorCell(1, errorColor)
+		case n.Unschedulable:
+			t.colorCell(1, warnColor)
+		default:
+			t.colorCell(1, okColor)
+		}
+		if len(n.Pressures) > 0 {
+			t.colorCell(2, errorColor)
+		}
+		switch {
+		case n.Heartbeat > heartbeatCritical:
+			t.colorCell(3, errorColor)
+		case n.Heartbeat > heartbeatWarning:
+			t.colorCell(3, warnColor)
+		}
+	}
+	t.print(color.Output)
+}
+
+// printNodeEvents prints the recent events of every node that has some, warnings in yellow.
+func printNodeEvents(infos []NodeHealthInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	warnColor := color.New(color.FgYellow)
+
+	for _, n := range infos {
+		if len(n.Events) == 0 {
+			continue
+		}
+		headerColor.Printf("Events of %s\n", n.Name)
+		for _, ev := range n.Events {
+			line := fmt.Sprintf("  %-10s %-25s %-10s %s", duration.HumanDuration(time.Since(eventTime(&ev))), ev.Reason,
+				fmt.Sprintf("x%d", max(ev.Count, 1)), firstLine(ev.Message, ""))
+			if ev.Type == corev1.EventTypeWarning {
+				warnColor.Println(line)
+			} else {
+				fmt.Println(line)
+			}
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+// node-shell command flags.
+var (
+	nodeShellImage     string
+	nodeShellNamespace string
+	nodeShellTimeout   time.Duration
+)
+
+// nodeShellLogin starts bash on the node when it has one, sh otherwise.
+const nodeShellLogin = "if command -v bash >/dev/null 2>&1; then exec bash -l; else exec sh -l; fi"
+
+// nodeShellCmd opens a root shell on a node through a privileged pod that enters the
+// namespaces of the node's init process.
+var nodeShellCmd = &cobra.Command{
+	Use:   "node-shell [NODE_PATTERN] [-- COMMAND...]",
+	Short: "Open a root shell, or run COMMAND, on the node matching [NODE_PATTERN] through a privileged pod that is deleted on exit.",
+	RunE:  runNodeShellFunc(configFlags),
+	// The shell owns the terminal.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	nodeShellCmd.Flags().StringVar(&nodeShellImage, "image", "busybox:1.36",
+		"Image of the node shell pod, must contain nsenter.")
+	nodeShellCmd.Flags().StringVar(&nodeShellNamespace, "pod-namespace", metav1.NamespaceSystem,
+		"Namespace the node shell pod runs in; its Pod Security level must allow privileged pods.")
+	nodeShellCmd.Flags().DurationVar(&nodeShellTimeout, "timeout", 2*time.Minute,
+		"How long to wait for the node shell pod to start.")
+	addDryRunFlag(nodeShellCmd)
+}
+
+// runNodeShellFunc returns a function that starts the node shell pod, attaches to it and
+// deletes it again when the shell ends.
+func runNodeShellFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		command := []string{"sh", "-c", nodeShellLogin}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			if len(args) > dash {
+				command = args[dash:]
+			}
+			args = args[:dash]
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a node pattern, for example:\n  kubectl helper node-shell worker-3\nor:\n  kubectl helper node-shell worker-3 -- crictl ps")
+		}
+		searchTerm := args[0]
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		// Ctrl+C goes to the shell on the node; these end the session and still clean up.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer stop()
+
+		nodes, err := findNodes(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		node, err := singleNode(nodes, searchTerm)
+		if err != nil {
+			return err
+		}
+
+		pod := nodeShellPod(node)
+		if dryRun != dryRunNone {
+			return printNodeShellPod(ctx, clientset, pod, dryRun)
+		}
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Starting privileged pod %s/%s on node %s...\n", nodeShellNamespace, pod.Name, node)
+		pod, err = clientset.CoreV1().Pods(nodeShellNamespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create node shell pod: %w", err)
+		}
+		defer deleteNodeShellPod(clientset, pod)
+
+		pod, err = waitForNodeShellPod(ctx, clientset, pod)
+		if err != nil {
+			return err
+		}
+		err = interactiveExecInPod(ctx, configFlags, clientset, pod, pod.Spec.Containers[0].Name,
+			append([]string{"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--"}, command...))
+		// Leaving the shell after a failing command isn't an error of node-shell.
+		var exitErr utilexec.CodeExitError
+		if err != nil && ctx.Err() == nil && !errors.As(err, &exitErr) {
+			return fmt.Errorf("node shell failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// singleNode picks the one node a pattern stands for; a full node name wins over the
+// others that merely contain it.
+func singleNode(nodes []string, pattern string) (string, error) {
+	switch len(nodes) {
+	case 0:
+		return "", fmt.Errorf("no nodes found matching the pattern: %s", pattern)
+	case 1:
+		return nodes[0], nil
+	}
+	for _, node := range nodes {
+		if node == pattern {
+			return node, nil
+		}
+	}
+	return "", fmt.Errorf("pattern %q matches %d nodes, please be more specific:\n  %s", pattern, len(nodes), strings.Join(nodes, "\n  "))
+}
+
+// nodeShellPod returns a privileged pod pinned to node that shares the host's PID, network
+// and IPC namespaces, so nsenter can reach the node's init process. It idles until the
+// shell is exec'd into it, for a day at most in case the cleanup never runs.
+func nodeShellPod(node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "helper-node-shell-" + rand.String(5),
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "kubectl-helper"},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:    node,
+			HostPID:     true,
+			HostNetwork: true,
+			HostIPC:     true,
+			// Reach nodes that are tainted, cordoned or under pressure, which are the ones
+			// most in need of a look.
+			Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: ptr.To[int64](0),
+			ActiveDeadlineSeconds:         ptr.To[int64](24 * 60 * 60),
+			Containers: []corev1.Container{{
+				Name:            "shell",
+				Image:           nodeShellImage,
+				Command:         []string{"sleep", "86400"},
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+			}},
+		},
+	}
+}
+
+// printNodeShellPod prints the pod a dry run would create as YAML. A server dry run
+// prints it as admitted, so a Pod Security level that rejects it shows up here.
+func printNodeShellPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, dryRun dryRunStrategy) error {
+	if dryRun == dryRunServer {
+		created, err := clientset.CoreV1().Pods(nodeShellNamespace).Create(ctx, pod, metav1.CreateOptions{DryRun: dryRun.serverDryRun()})
+		if err != nil {
+			return fmt.Errorf("failed to create node shell pod: %w", err)
+		}
+		pod = created
+	}
+	pod = pod.DeepCopy()
+	pod.APIVersion, pod.Kind, pod.Namespace = "v1", "Pod", nodeShellNamespace
+	pod.ManagedFields = nil
+	data, err := yaml.Marshal(pod)
+	if err != nil {
+		return fmt.Errorf("failed to encode pod %s: %w", pod.Name, err)
+	}
+	os.Stdout.Write(data)
+	printChange(dryRun, "pod/%s created", pod.Name)
+	return nil
+}
+
+// waitForNodeShellPod waits until the pod runs, failing early when it can't start.
+func waitForNodeShellPod(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) (*corev1.Pod, error) {
+	var current *corev1.Pod
+	err := wait.PollUntilContextTimeout(ctx, time.Second, nodeShellTimeout, true, func(ctx context.Context) (bool, error) {
+		var err error
+		current, err = clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		switch current.Status.Phase {
+		case corev1.PodRunning:
+			return true, nil
+		case corev1.PodFailed, corev1.PodSucceeded:
+			return false, fmt.Errorf("pod %s is %s: %s", pod.Name, current.Status.Phase, current.Status.Message)
+		}
+		for _, st := range current.Status.ContainerStatuses {
+			if w := st.State.Waiting; w != nil && (w.Reason == "ErrImagePull" || w.Reason == "ImagePullBackOff" || w.Reason == "CreateContainerConfigError") {
+				return false, fmt.Errorf("pod %s can't start: %s: %s", pod.Name, w.Reason, w.Message)
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("node shell pod didn't start: %w", err)
+	}
+	return current, nil
+}
+
+// deleteNodeShellPod removes the pod right away. It doesn't use the command's context,
+// which is already canceled when the session was interrupted.
+func deleteNodeShellPod(clientset kubernetes.Interface, pod *corev1.Pod) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)})
+	if err != nil {
+		color.New(color.FgRed).Fprintf(os.Stderr, "Failed to delete pod %s/%s, please delete it yourself: %v\n", pod.Namespace, pod.Name, err)
+		return
+	}
+	color.New(color.FgCyan).Fprintf(os.Stderr, "Deleted pod %s/%s\n", pod.Namespace, pod.Name)
+}
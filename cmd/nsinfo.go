@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NamespaceInfo summarizes one namespace.
+type NamespaceInfo struct {
+	Name  string
+	Phase corev1.NamespacePhase
+	Age   time.Duration
+	Pods  int
+	// RunningPods counts the pods that are running with all containers ready.
+	RunningPods int
+	FailedPods  int
+	Deployments int
+	// AvailableDeployments counts the Deployments with all replicas available.
+	AvailableDeployments int
+	Services             int
+	Quotas               []QuotaUsage
+	LimitRanges          []LimitRangeItem
+	// Problem explains a namespace that is stuck terminating, from its conditions.
+	Problem string
+	// Forbidden holds the resources, e.g. "pods", the user may not list in the namespace.
+	Forbidden map[string]bool
+}
+
+// QuotaUsage is the usage of one resource of a ResourceQuota.
+type QuotaUsage struct {
+	Quota    string
+	Resource corev1.ResourceName
+	Used     resource.Quantity
+	Hard     resource.Quantity
+}
+
+// LimitRangeItem is one resource limit of a LimitRange.
+type LimitRangeItem struct {
+	LimitRange     string
+	Type           corev1.LimitType
+	Resource       corev1.ResourceName
+	DefaultRequest string
+	Default        string
+	Min            string
+	Max            string
+}
+
+// Quota usage from these percentages on is shown in yellow and red.
+const (
+	quotaWarnPercent  = 75
+	quotaErrorPercent = 90
+)
+
+// nsinfoCmd summarizes the matching namespaces.
+var nsinfoCmd = &cobra.Command{
+	Use:   "nsinfo [NAMESPACE_PATTERN]",
+	Short: "Summarize namespaces containing [NAMESPACE_PATTERN]: status, pod, deployment and service counts, ResourceQuota usage and LimitRanges.",
+	RunE:  runNsinfoFunc(configFlags),
+}
+
+// runNsinfoFunc returns a function that prints the summary, quota and limit tables of the
+// matching namespaces.
+func runNsinfoFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a namespace pattern, for example:\n  kubectl helper nsinfo team-a\nor:\n  kubectl helper nsinfo \"\"")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		namespaces, err := findNamespaces(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(namespaces) == 0 {
+			fmt.Printf("No namespaces found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var infos []NamespaceInfo
+		for i := range namespaces {
+			info, err := namespaceInfo(ctx, clientset, &namespaces[i])
+			if err != nil {
+				return err
+			}
+			infos = append(infos, info)
+		}
+		historyMatches = len(infos)
+
+		printNamespaceTable(infos)
+		printQuotaTable(infos)
+		printLimitRangeTable(infos)
+		return nil
+	}
+}
+
+// findNamespaces returns the namespaces whose name contains pattern, sorted by name. Tenants
+// usually can't list namespaces, so then the current context's namespace is used.
+func findNamespaces(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pattern string) ([]corev1.Namespace, error) {
+	list, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil && !isForbidden(err) {
+		return nil, fmt.Errorf("failed to retrieve namespaces: %w", err)
+	}
+	var items []corev1.Namespace
+	if err == nil {
+		items = list.Items
+	} else {
+		names, err := targetNamespaces(configFlags)
+		if err != nil {
+			return nil, err
+		}
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Not allowed to list namespaces, only looking at %s\n", strings.Join(names, ", "))
+		for _, name := range names {
+			if name == metav1.NamespaceAll {
+				continue
+			}
+			ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+			switch {
+			case isForbidden(err):
+				// The namespace itself is still worth summarizing.
+				ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			case err != nil:
+				return nil, fmt.Errorf("failed to retrieve namespace %s: %w", name, err)
+			}
+			items = append(items, *ns)
+		}
+	}
+
+	var namespaces []corev1.Namespace
+	for _, ns := range items {
+		if matchesPattern(ns.Name, pattern) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces, nil
+}
+
+// namespaceInfo counts the objects of ns and reads its quotas and limit ranges.
+func namespaceInfo(ctx context.Context, clientset kubernetes.Interface, ns *corev1.Namespace) (NamespaceInfo, error) {
+	info := NamespaceInfo{Name: ns.Name, Phase: ns.Status.Phase, Forbidden: map[string]bool{}}
+	if !ns.CreationTimestamp.IsZero() {
+		info.Age = time.Since(ns.CreationTimestamp.Time)
+	}
+	if ns.Status.Phase == corev1.NamespaceTerminating {
+		for _, cond := range ns.Status.Conditions {
+			if cond.Status == corev1.ConditionTrue {
+				info.Problem = firstLine(cond.Message, string(cond.Type))
+				break
+			}
+		}
+	}
+
+	// A tenant may only see part of the namespaces; the others are still summarized.
+	pods, err := clientset.CoreV1().Pods(ns.Name).List(ctx, metav1.ListOptions{})
+	switch {
+	case isForbidden(err):
+		info.Forbidden["pods"] = true
+	case err != nil:
+		return info, fmt.Errorf("failed to retrieve pods of %s: %w", ns.Name, err)
+	default:
+		info.Pods = len(pods.Items)
+		for _, pod := range pods.Items {
+			switch pod.Status.Phase {
+			case corev1.PodRunning:
+				if isPodReady(&pod) {
+					info.RunningPods++
+				}
+			case corev1.PodFailed:
+				info.FailedPods++
+			}
+		}
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(ns.Name).List(ctx, metav1.ListOptions{})
+	switch {
+	case isForbidden(err):
+		info.Forbidden["deployments"] = true
+	case err != nil:
+		return info, fmt.Errorf("failed to retrieve deployments of %s: %w", ns.Name, err)
+	default:
+		info.Deployments = len(deployments.Items)
+		for _, d := range deployments.Items {
+			want := int32(1)
+			if d.Spec.Replicas != nil {
+				want = *d.Spec.Replicas
+			}
+			if d.Status.AvailableReplicas >= want {
+				info.AvailableDeployments++
+			}
+		}
+	}
+
+	services, err := clientset.CoreV1().Services(ns.Name).List(ctx, metav1.ListOptions{})
+	switch {
+	case isForbidden(err):
+		info.Forbidden["services"] = true
+	case err != nil:
+		return info, fmt.Errorf("failed to retrieve services of %s: %w", ns.Name, err)
+	default:
+		info.Services = len(services.Items)
+	}
+
+	// Quotas and limit ranges are extra information, not worth failing over.
+	quotas, err := clientset.CoreV1().ResourceQuotas(ns.Name).List(ctx, metav1.ListOptions{})
+	if err != nil && !isForbidden(err) {
+		return info, fmt.Errorf("failed to retrieve resourcequotas of %s: %w", ns.Name, err)
+	}
+	if err == nil {
+		for _, q := range quotas.Items {
+			for name, hard := range q.Status.Hard {
+				info.Quotas = append(info.Quotas, QuotaUsage{Quota: q.Name, Resource: name, Used: q.Status.Used[name], Hard: hard})
+			}
+		}
+		sort.Slice(info.Quotas, func(i, j int) bool {
+			if info.Quotas[i].Quota != info.Quotas[j].Quota {
+				return info.Quotas[i].Quota < info.Quotas[j].Quota
+			}
+			return info.Quotas[i].Resource < info.Quotas[j].Resource
+		})
+	}
+
+	limitRanges, err := clientset.CoreV1().LimitRanges(ns.Name).List(ctx, metav1.ListOptions{})
+	if err != nil && !isForbidden(err) {
+		return info, fmt.Errorf("failed to retrieve limitranges of %s: %w", ns.Name, err)
+	}
+	if err == nil {
+		for _, lr := range limitRanges.Items {
+			info.LimitRanges = append(info.LimitRanges, limitRangeItems(lr)...)
+		}
+	}
+	return info, nil
+}
+
+// limitRangeItems flattens a LimitRange into one item per limit type and resource.
+func limitRangeItems(lr corev1.LimitRange) []LimitRangeItem {
+	quantity := func(list corev1.ResourceList, name corev1.ResourceName) string {
+		if q, ok := list[name]; ok {
+			return q.String()
+		}
+		return "-"
+	}
+
+	var items []LimitRangeItem
+	for _, limit := range lr.Spec.Limits {
+		names := map[corev1.ResourceName]bool{}
+		for _, list := range []corev1.ResourceList{limit.DefaultRequest, limit.Default, limit.Min, limit.Max} {
+			for name := range list {
+				names[name] = true
+			}
+		}
+		sorted := make([]corev1.ResourceName, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		for _, name := range sorted {
+			items = append(items, LimitRangeItem{
+				LimitRange:     lr.Name,
+				Type:           limit.Type,
+				Resource:       name,
+				DefaultRequest: quantity(limit.DefaultRequest, name),
+				Default:        quantity(limit.Default, name),
+				Min:            quantity(limit.Min, name),
+				Max:            quantity(limit.Max, name),
+			})
+		}
+	}
+	return items
+}
+
+// quotaPercent returns how much of the hard limit is used, in percent. A zero limit that
+// is used counts as full.
+func quotaPercent(q QuotaUsage) float64 {
+	hard := q.Hard.AsApproximateFloat64()
+	if hard == 0 {
+		if q.Used.IsZero() {
+			return 0
+		}
+		return 100
+	}
+	return 100 * q.Used.AsApproximateFloat64() / hard
+}
+
+// quotaColor returns the color of a quota usage, nil below the warning level.
+func quotaColor(percent float64) *color.Color {
+	switch {
+	case percent >= quotaErrorPercent:
+		return color.New(color.FgRed, color.Bold)
+	case percent >= quotaWarnPercent:
+		return color.New(color.FgYellow)
+	}
+	return nil
+}
+
+// printNamespaceTable prints a row per namespace with the fullest quota resource.
+func printNamespaceTable(infos []NamespaceInfo) {
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("NAMESPACE", "STATUS", "AGE", "PODS READY", "FAILED", "DEPLOYMENTS AVAILABLE", "SERVICES", "FULLEST QUOTA", "LIMITRANGES")
+	for _, ns := range infos {
+		status, age := string(ns.Phase), "-"
+		if status == "" {
+			status = "unknown"
+		}
+		if ns.Problem != "" {
+			status += ": " + ns.Problem
+		}
+		if ns.Age > 0 {
+			age = duration.HumanDuration(ns.Age)
+		}
+		fullest, fullestPercent := "-", -1.0
+		for _, q := range ns.Quotas {
+			if p := quotaPercent(q); p > fullestPercent {
+				fullest, fullestPercent = fmt.Sprintf("%s %.0f%%", q.Resource, p), p
+			}
+		}
+		limitRanges := "-"
+		if len(ns.LimitRanges) > 0 {
+			names := map[string]bool{}
+			for _, lr := range ns.LimitRanges {
+				names[lr.LimitRange] = true
+			}
+			limitRanges = fmt.Sprintf("%d", len(names))
+		}
+		podsReady, failed := fmt.Sprintf("%d/%d", ns.RunningPods, ns.Pods), fmt.Sprintf("%d", ns.FailedPods)
+		if ns.Forbidden["pods"] {
+			podsReady, failed = "forbidden", "forbidden"
+		}
+		deployments := fmt.Sprintf("%d/%d", ns.AvailableDeployments, ns.Deployments)
+		if ns.Forbidden["deployments"] {
+			deployments = "forbidden"
+		}
+		services := fmt.Sprintf("%d", ns.Services)
+		if ns.Forbidden["services"] {
+			services = "forbidden"
+		}
+		t.addRow(ns.Name, status, age, podsReady, failed, deployments, services, fullest, limitRanges)
+
+		if ns.Phase != corev1.NamespaceActive && ns.Phase != "" {
+			t.colorCell(1, errorColor)
+		}
+		if ns.FailedPods > 0 {
+			t.colorCell(4, warnColor)
+		}
+		if ns.AvailableDeployments < ns.Deployments {
+			t.colorCell(5, warnColor)
+		}
+		if c := quotaColor(fullestPercent); c != nil {
+			t.colorCell(7, c)
+		}
+	}
+	t.print(color.Output)
+}
+
+// printQuotaTable prints a row per quota resource of the namespaces that have quotas.
+func printQuotaTable(infos []NamespaceInfo) {
+	t := newTable("NAMESPACE", "QUOTA", "RESOURCE", "USED", "HARD", "USAGE")
+	for _, ns := range infos {
+		for _, q := range ns.Quotas {
+			p := quotaPercent(q)
+			t.addRow(ns.Name, q.Quota, string(q.Resource), q.Used.String(), q.Hard.String(), fmt.Sprintf("%.0f%%", p))
+			if c := quotaColor(p); c != nil {
+				t.colorCell(5, c)
+			}
+		}
+	}
+	if len(t.rows) > 0 {
+		t.print(color.Output)
+	}
+}
+
+// printLimitRangeTable prints a row per limit of the namespaces that have limit ranges.
+func printLimitRangeTable(infos []NamespaceInfo) {
+	t := newTable("NAMESPACE", "LIMITRANGE", "TYPE", "RESOURCE", "DEFAULT REQUEST", "DEFAULT LIMIT", "MIN", "MAX")
+	for _, ns := range infos {
+		for _, l := range ns.LimitRanges {
+			t.addRow(ns.Name, l.LimitRange, string(l.Type), string(l.Resource), l.DefaultRequest, l.Default, l.Min, l.Max)
+		}
+	}
+	if len(t.rows) > 0 {
+		t.print(color.Output)
+	}
+}
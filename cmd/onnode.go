@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodePodsInfo holds the pods of one node and what they request of it.
+type NodePodsInfo struct {
+	Node *corev1.Node
+	// Pods are sorted by namespace, then name.
+	Pods []*corev1.Pod
+}
+
+// onNodeFinished also lists the pods that succeeded or failed.
+var onNodeFinished bool
+
+// onNodeCmd lists what runs on the matching nodes.
+var onNodeCmd = &cobra.Command{
+	Use:   "on-node [NODE_PATTERN]",
+	Short: "List the pods on nodes containing [NODE_PATTERN], grouped by namespace, with their CPU and memory requests against the node's allocatable.",
+	RunE:  runOnNodeFunc(configFlags),
+}
+
+func init() {
+	onNodeCmd.Flags().BoolVar(&onNodeFinished, "include-finished", false,
+		"Also list succeeded and failed pods, which no longer hold their requests.")
+}
+
+// runOnNodeFunc returns a function that prints a table of pods per matching node.
+func runOnNodeFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a node pattern, for example:\n  kubectl helper on-node worker-3\nor:\n  kubectl helper on-node pool-a --include-finished")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		nodes, err := findNodes(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		historyMatches = len(nodes)
+		if len(nodes) == 0 {
+			fmt.Printf("No nodes found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		resolver, err := newOwnerResolver(configFlags)
+		if err != nil {
+			return err
+		}
+		for _, name := range nodes {
+			info, err := nodePods(ctx, clientset, name)
+			if err != nil {
+				return err
+			}
+			printNodePodsTable(ctx, resolver, info)
+		}
+		return nil
+	}
+}
+
+// nodePods fetches a node and the pods scheduled on it.
+func nodePods(ctx context.Context, clientset kubernetes.Interface, name string) (NodePodsInfo, error) {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return NodePodsInfo{}, fmt.Errorf("failed to retrieve node %s: %w", name, err)
+	}
+	list, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + name})
+	if err != nil {
+		return NodePodsInfo{}, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+
+	info := NodePodsInfo{Node: node}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if !onNodeFinished && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+			continue
+		}
+		info.Pods = append(info.Pods, pod)
+	}
+	sort.Slice(info.Pods, func(i, j int) bool {
+		a, b := info.Pods[i], info.Pods[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return info, nil
+}
+
+// podRequests returns the requests the scheduler accounts the pod for: its containers and
+// sidecars, or the largest init container if that is more, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	add := func(list, into corev1.ResourceList) {
+		for name, q := range list {
+			sum := into[name]
+			sum.Add(q)
+			into[name] = sum
+		}
+	}
+
+	isSidecar := func(c corev1.Container) bool {
+		return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+	}
+	for _, c := range pod.Spec.Containers {
+		add(c.Resources.Requests, total)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if isSidecar(c) {
+			add(c.Resources.Requests, total)
+		}
+	}
+	// A regular init container runs alone, next to the sidecars started before it.
+	sidecars := corev1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		if isSidecar(c) {
+			add(c.Resources.Requests, sidecars)
+			continue
+		}
+		running := corev1.ResourceList{}
+		add(sidecars, running)
+		add(c.Resources.Requests, running)
+		for name, q := range running {
+			if cur := total[name]; q.Cmp(cur) > 0 {
+				total[name] = q
+			}
+		}
+	}
+	add(pod.Spec.Overhead, total)
+	return total
+}
+
+// formatCPU renders a CPU quantity in millicores, "-" for none.
+func formatCPU(q resource.Quantity) string {
+	if q.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+// formatMemory renders a memory quantity with a binary unit, "-" for none.
+func formatMemory(q resource.Quantity) string {
+	if q.IsZero() {
+		return "-"
+	}
+	return formatBytes(q.Value())
+}
+
+// requestPercent returns requested as a percentage of allocatable, 0 when unknown.
+func requestPercent(requested, allocatable resource.Quantity) float64 {
+	if allocatable.IsZero() {
+		return 0
+	}
+	return 100 * float64(requested.MilliValue()) / float64(allocatable.MilliValue())
+}
+
+// printNodePodsTable prints the pods of a node with a subtotal per namespace, then the
+// node's total. Totals over the node's allocatable are red.
+func printNodePodsTable(ctx context.Context, resolver *ownerResolver, info NodePodsInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	totalColor := color.New(color.Bold)
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	allocatable := info.Node.Status.Allocatable
+	cpuAllocatable, memAllocatable := allocatable[corev1.ResourceCPU], allocatable[corev1.ResourceMemory]
+
+	t := newTable("NAMESPACE", "POD", "OWNER", "STATUS", "CPU REQ", "MEM REQ", "CPU %", "MEM %")
+	addTotal := func(label string, pods int, cpu, mem resource.Quantity) {
+		cpuPercent, memPercent := requestPercent(cpu, cpuAllocatable), requestPercent(mem, memAllocatable)
+		t.addRow(label, fmt.Sprintf("%d pod(s)", pods), "", "", formatCPU(cpu), formatMemory(mem),
+			fmt.Sprintf("%.0f%%", cpuPercent), fmt.Sprintf("%.0f%%", memPercent))
+		for col := 0; col < 8; col++ {
+			t.colorCell(col, totalColor)
+		}
+		if cpuPercent > 100 {
+			t.colorCell(6, errorColor)
+		}
+		if memPercent > 100 {
+			t.colorCell(7, errorColor)
+		}
+	}
+
+	var nodeCPU, nodeMem, nsCPU, nsMem resource.Quantity
+	nsPods := 0
+	for i, pod := range info.Pods {
+		requests := podRequests(pod)
+		cpu, mem := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+		kind, name := resolver.podWorkload(ctx, pod)
+		status := podStatusText(pod)
+		t.addRow(pod.Namespace, pod.Name, kind+"/"+name, status, formatCPU(cpu), formatMemory(mem),
+			fmt.Sprintf("%.0f%%", requestPercent(cpu, cpuAllocatable)), fmt.Sprintf("%.0f%%", requestPercent(mem, memAllocatable)))
+		// Pods without requests are the usual noisy neighbors: the scheduler doesn't account for them.
+		if cpu.IsZero() {
+			t.colorCell(4, warnColor)
+		}
+		if mem.IsZero() {
+			t.colorCell(5, warnColor)
+		}
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+			t.colorCell(3, warnColor)
+		}
+
+		nsCPU.Add(cpu)
+		nsMem.Add(mem)
+		nsPods++
+		if i == len(info.Pods)-1 || info.Pods[i+1].Namespace != pod.Namespace {
+			addTotal(pod.Namespace+" total", nsPods, nsCPU, nsMem)
+			nodeCPU.Add(nsCPU)
+			nodeMem.Add(nsMem)
+			nsCPU, nsMem, nsPods = resource.Quantity{}, resource.Quantity{}, 0
+		}
+	}
+	addTotal("node total", len(info.Pods), nodeCPU, nodeMem)
+
+	headerColor.Printf("Node %s: %s CPU, %s memory allocatable\n", info.Node.Name, formatCPU(cpuAllocatable), formatMemory(memAllocatable))
+	t.print(color.Output)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+)
+
+// ownerKindNames renames controllers whose kind clashes with a built-in one.
+var ownerKindNames = map[schema.GroupKind]string{
+	{Group: "serving.knative.dev", Kind: "Service"}: "KnativeService",
+}
+
+// Labels Flux puts on every object a Kustomization applies.
+const (
+	fluxKustomizationName      = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNamespace = "kustomize.toolkit.fluxcd.io/namespace"
+)
+
+// ownerResolver follows controller ownerReferences up to the top-level workload with the
+// dynamic client, so CRD-based controllers like Argo Rollouts or Knative Services are
+// found as well as Deployments.
+type ownerResolver struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+	// objects caches every owner looked up by namespace, group, kind and name; nil when
+	// it couldn't be read.
+	objects map[string]*unstructured.Unstructured
+}
+
+// newOwnerResolver creates a resolver from the kubeconfig-based flags.
+func newOwnerResolver(configFlags *genericclioptions.ConfigFlags) (*ownerResolver, error) {
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	mapper, err := configFlags.ToRESTMapper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the cluster's resources: %w", err)
+	}
+	return &ownerResolver{client: client, mapper: mapper, objects: map[string]*unstructured.Unstructured{}}, nil
+}
+
+// get returns an owner object, or nil when its kind is unknown or it can't be read.
+func (r *ownerResolver) get(ctx context.Context, namespace string, ref metav1.OwnerReference) *unstructured.Unstructured {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil
+	}
+	key := namespace + "/" + gv.Group + "/" + ref.Kind + "/" + ref.Name
+	if obj, ok := r.objects[key]; ok {
+		return obj
+	}
+	var obj *unstructured.Unstructured
+	if mapping, err := r.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version); err == nil {
+		obj, err = r.client.Resource(mapping.Resource).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			obj = nil
+		}
+	}
+	r.objects[key] = obj
+	return obj
+}
+
+// top follows controller references from ref up to the top-level owner. It returns the
+// topmost object that could be read, nil if none could, and the reference to the top.
+func (r *ownerResolver) top(ctx context.Context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, metav1.OwnerReference) {
+	var top *unstructured.Unstructured
+	// Owner chains are short; the limit only guards against reference loops.
+	for depth := 0; depth < 10; depth++ {
+		obj := r.get(ctx, namespace, ref)
+		if obj == nil {
+			break
+		}
+		top = obj
+		parent := metav1.GetControllerOfNoCopy(obj)
+		if parent == nil {
+			break
+		}
+		ref = *parent
+	}
+	return top, ref
+}
+
+// resolve returns the top-level owner of an object controlled by ref as "Kind/Name", and
+// the Flux Kustomization that applied it as "Kustomization/namespace/name", if any.
+// Owners that can't be read end the chain, so missing RBAC only makes the answer shorter.
+func (r *ownerResolver) resolve(ctx context.Context, namespace string, ref metav1.OwnerReference) (owner, managedBy string) {
+	top, ref := r.top(ctx, namespace, ref)
+
+	kind := ref.Kind
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil {
+		if name, ok := ownerKindNames[schema.GroupKind{Group: gv.Group, Kind: ref.Kind}]; ok {
+			kind = name
+		}
+	}
+	owner = kind + "/" + ref.Name
+
+	if top != nil {
+		labels := top.GetLabels()
+		if name := labels[fluxKustomizationName]; name != "" {
+			managedBy = "Kustomization/" + labels[fluxKustomizationNamespace] + "/" + name
+		}
+	}
+	return owner, managedBy
+}
+
+// podOwner is resolve for a pod with podLabels. A ReplicaSet that can't be read still
+// names its Deployment through the pod-template-hash label.
+func (r *ownerResolver) podOwner(ctx context.Context, namespace string, ref metav1.OwnerReference, podLabels map[string]string) (owner, managedBy string) {
+	owner, managedBy = r.resolve(ctx, namespace, ref)
+	if ref.Kind == "ReplicaSet" && r.get(ctx, namespace, ref) == nil {
+		if hash, ok := podLabels["pod-template-hash"]; ok && strings.HasSuffix(ref.Name, "-"+hash) {
+			owner = "Deployment/" + strings.TrimSuffix(ref.Name, "-"+hash)
+		}
+	}
+	return owner, managedBy
+}
+
+// podWorkload returns the kind and name of the top-level workload that controls pod, e.g.
+// a Deployment, Argo Rollout or CronJob rather than the ReplicaSet or Job. Bare pods
+// report themselves.
+func (r *ownerResolver) podWorkload(ctx context.Context, pod *corev1.Pod) (kind, name string) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "Pod", pod.Name
+	}
+	owner, _ := r.podOwner(ctx, pod.Namespace, *ref, pod.Labels)
+	kind, name, _ = strings.Cut(owner, "/")
+	return kind, name
+}
+
+// resolvePodOwners replaces the direct controller in the Owner of every pod with its
+// top-level owner and fills in ManagedBy.
+func resolvePodOwners(ctx context.Context, configFlags *genericclioptions.ConfigFlags, pods []PodInfo) error {
+	resolver, err := newOwnerResolver(configFlags)
+	if err != nil {
+		return err
+	}
+	for i := range pods {
+		if pods[i].controller == nil {
+			continue
+		}
+		pods[i].Owner, pods[i].ManagedBy = resolver.podOwner(ctx, pods[i].Namespace, *pods[i].controller, pods[i].Labels)
+	}
+	return nil
+}
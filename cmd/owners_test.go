@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+)
+
+// testOwnerKinds are the kinds the test resolver knows, with their list kinds.
+var testOwnerKinds = []schema.GroupVersionKind{
+	{Group: "apps", Version: "v1", Kind: "ReplicaSet"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+	{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+	{Group: "serving.knative.dev", Version: "v1", Kind: "Service"},
+}
+
+// ownerRef returns a controller reference to the object of gvk named name.
+func ownerRef(gvk schema.GroupVersionKind, name string) metav1.OwnerReference {
+	return metav1.OwnerReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: name, Controller: ptr.To(true)}
+}
+
+// ownerObject returns an object of gvk in the default namespace, controlled by owner if set.
+func ownerObject(gvk schema.GroupVersionKind, name string, owner *metav1.OwnerReference, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	if owner != nil {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*owner})
+	}
+	return obj
+}
+
+// newTestOwnerResolver returns a resolver reading objects from a fake dynamic client.
+func newTestOwnerResolver(objects ...runtime.Object) *ownerResolver {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, gvk := range testOwnerKinds {
+		mapper.Add(gvk, meta.RESTScopeNamespace)
+		mapping, _ := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		listKinds[mapping.Resource] = gvk.Kind + "List"
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	return &ownerResolver{client: client, mapper: mapper, objects: map[string]*unstructured.Unstructured{}}
+}
+
+func TestPodWorkload(t *testing.T) {
+	replicaSet, deployment, job, cronJob, rollout, knativeService := testOwnerKinds[0], testOwnerKinds[1], testOwnerKinds[2], testOwnerKinds[3], testOwnerKinds[4], testOwnerKinds[5]
+	resolver := newTestOwnerResolver(
+		ownerObject(deployment, "web", nil, nil),
+		ownerObject(replicaSet, "web-5d8f", ptr.To(ownerRef(deployment, "web")), nil),
+		ownerObject(rollout, "canary", nil, nil),
+		ownerObject(replicaSet, "canary-7c9b", ptr.To(ownerRef(rollout, "canary")), nil),
+		ownerObject(cronJob, "nightly", nil, nil),
+		ownerObject(job, "nightly-2900", ptr.To(ownerRef(cronJob, "nightly")), nil),
+		ownerObject(knativeService, "hello", nil, nil),
+		ownerObject(deployment, "hello-00001-deployment", ptr.To(ownerRef(knativeService, "hello")), nil),
+		ownerObject(replicaSet, "hello-00001-deployment-6f4d", ptr.To(ownerRef(deployment, "hello-00001-deployment")), nil),
+		ownerObject(replicaSet, "bare-1a2b", nil, nil),
+	)
+
+	tests := []struct {
+		name      string
+		owner     *metav1.OwnerReference
+		labels    map[string]string
+		wantKind  string
+		wantName  string
+		wantOwner string
+	}{
+		{
+			name:     "bare pod",
+			wantKind: "Pod", wantName: "pod",
+		},
+		{
+			name:     "deployment",
+			owner:    ptr.To(ownerRef(replicaSet, "web-5d8f")),
+			labels:   map[string]string{"pod-template-hash": "5d8f"},
+			wantKind: "Deployment", wantName: "web",
+		},
+		{
+			name:     "argo rollout",
+			owner:    ptr.To(ownerRef(replicaSet, "canary-7c9b")),
+			labels:   map[string]string{"pod-template-hash": "7c9b"},
+			wantKind: "Rollout", wantName: "canary",
+		},
+		{
+			name:     "cronjob",
+			owner:    ptr.To(ownerRef(job, "nightly-2900")),
+			wantKind: "CronJob", wantName: "nightly",
+		},
+		{
+			name:     "knative service",
+			owner:    ptr.To(ownerRef(replicaSet, "hello-00001-deployment-6f4d")),
+			labels:   map[string]string{"pod-template-hash": "6f4d"},
+			wantKind: "KnativeService", wantName: "hello",
+		},
+		{
+			name:     "unreadable replicaset named by its deployment",
+			owner:    ptr.To(ownerRef(replicaSet, "api-7d9f")),
+			labels:   map[string]string{"pod-template-hash": "7d9f"},
+			wantKind: "Deployment", wantName: "api",
+		},
+		{
+			name:     "unreadable replicaset without hash",
+			owner:    ptr.To(ownerRef(replicaSet, "api-7d9f")),
+			wantKind: "ReplicaSet", wantName: "api-7d9f",
+		},
+		{
+			name:     "readable replicaset without owner",
+			owner:    ptr.To(ownerRef(replicaSet, "bare-1a2b")),
+			labels:   map[string]string{"pod-template-hash": "1a2b"},
+			wantKind: "ReplicaSet", wantName: "bare-1a2b",
+		},
+		{
+			name:     "unknown kind",
+			owner:    &metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Widget", Name: "w", Controller: ptr.To(true)},
+			wantKind: "Widget", wantName: "w",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default", Labels: tt.labels}}
+			if tt.owner != nil {
+				pod.OwnerReferences = []metav1.OwnerReference{*tt.owner}
+			}
+			kind, name := resolver.podWorkload(context.Background(), pod)
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Errorf("podWorkload() = %s/%s, want %s/%s", kind, name, tt.wantKind, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestPodOwnerManagedBy(t *testing.T) {
+	replicaSet, deployment := testOwnerKinds[0], testOwnerKinds[1]
+	resolver := newTestOwnerResolver(
+		ownerObject(deployment, "web", nil, map[string]string{
+			fluxKustomizationName:      "apps",
+			fluxKustomizationNamespace: "flux-system",
+		}),
+		ownerObject(replicaSet, "web-5d8f", ptr.To(ownerRef(deployment, "web")), nil),
+	)
+	owner, managedBy := resolver.podOwner(context.Background(), "default", ownerRef(replicaSet, "web-5d8f"), nil)
+	if owner != "Deployment/web" || managedBy != "Kustomization/flux-system/apps" {
+		t.Errorf("podOwner() = %q, %q; want Deployment/web, Kustomization/flux-system/apps", owner, managedBy)
+	}
+}
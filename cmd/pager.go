@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// noPager disables piping long output through the pager.
+var noPager bool
+
+// terminalStdout is the real stdout; startPager swaps os.Stdout for a pipe.
+var terminalStdout = os.Stdout
+
+// pagerAnnotation set to "false" on a command keeps its output off the pager, for
+// interactive and long running commands.
+const pagerAnnotation = "pager"
+
+func init() {
+	RootCmd.PersistentFlags().BoolVar(&noPager, "no-pager", false,
+		"Never pipe output longer than the terminal through $PAGER.")
+}
+
+// pager collects stdout while a command runs and hands it to $PAGER once it no longer
+// fits on the screen.
+type pager struct {
+	w    *os.File
+	done chan error
+}
+
+// activePager is the pager started for the running command, nil when there is none.
+var activePager *pager
+
+// stdoutIsTerminal reports whether the real stdout is a terminal.
+func stdoutIsTerminal() bool {
+	fd := terminalStdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// startPager redirects stdout, colored output included, into the pager when stdout is
+// a terminal. Output that fits on the screen is written out as is when the command ends.
+func startPager(cmd *cobra.Command) error {
+	if !wantsPager(cmd) || !stdoutIsTerminal() {
+		return nil
+	}
+	_, height, err := term.GetSize(int(terminalStdout.Fd()))
+	if err != nil || height <= 0 {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	p := &pager{w: w, done: make(chan error, 1)}
+	go func() {
+		// Keep one row free for the shell prompt.
+		p.done <- pageOutput(r, height-1)
+		r.Close()
+	}()
+	os.Stdout = w
+	color.Output = w
+	activePager = p
+	return nil
+}
+
+// wantsPager reports whether the output of cmd may go through the pager: not with
+// --no-pager, for commands annotated with pagerAnnotation "false", or while --follow streams.
+func wantsPager(cmd *cobra.Command) bool {
+	if noPager || cmd.Annotations[pagerAnnotation] == "false" {
+		return false
+	}
+	if follow := cmd.Flags().Lookup("follow"); follow != nil && follow.Value.String() == "true" {
+		return false
+	}
+	return true
+}
+
+// stopPager flushes the output of the command and waits for the user to quit the pager.
+func stopPager() {
+	if activePager == nil {
+		return
+	}
+	activePager.w.Close()
+	err := <-activePager.done
+	os.Stdout = terminalStdout
+	color.Output = terminalStdout
+	activePager = nil
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pager failed: %v\n", err)
+	}
+}
+
+// pageOutput buffers r until it has more than height lines, then starts the pager and
+// streams the rest into it. Shorter output goes straight to the terminal.
+func pageOutput(r io.Reader, height int) error {
+	reader := bufio.NewReader(r)
+	var buffered bytes.Buffer
+	for lines := 0; lines <= height; lines++ {
+		line, err := reader.ReadBytes('\n')
+		buffered.Write(line)
+		if err == io.EOF {
+			_, err = terminalStdout.Write(buffered.Bytes())
+			return err
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	pagerCmd := pagerCommand()
+	stdin, err := pagerCmd.StdinPipe()
+	if err == nil {
+		err = pagerCmd.Start()
+	}
+	if err != nil {
+		// Without a pager the output still has to reach the user.
+		terminalStdout.Write(buffered.Bytes())
+		io.Copy(terminalStdout, reader)
+		return fmt.Errorf("failed to start %s: %w", pagerCmd.Path, err)
+	}
+	// The user quitting the pager early closes stdin; the rest of the output is dropped.
+	if _, err := buffered.WriteTo(stdin); err == nil {
+		io.Copy(stdin, reader)
+	}
+	io.Copy(io.Discard, reader)
+	stdin.Close()
+	return pagerCmd.Wait()
+}
+
+// pagerCommand builds $PAGER, defaulting to less. less gets -R so the colors survive.
+func pagerCommand() *exec.Cmd {
+	args := strings.Fields(os.Getenv("PAGER"))
+	if len(args) == 0 {
+		args = []string{"less"}
+	}
+	pagerCmd := exec.Command(args[0], args[1:]...)
+	pagerCmd.Stdout, pagerCmd.Stderr = terminalStdout, os.Stderr
+	if _, set := os.LookupEnv("LESS"); !set {
+		pagerCmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	return pagerCmd
+}
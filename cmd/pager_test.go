@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWantsPager(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		followFlag  bool
+		args        []string
+		noPager     bool
+		want        bool
+	}{
+		{name: "plain command", want: true},
+		{name: "--no-pager", noPager: true, want: false},
+		{name: "annotated", annotations: map[string]string{pagerAnnotation: "false"}, want: false},
+		{name: "other annotation", annotations: map[string]string{"other": "false"}, want: true},
+		{name: "follow flag not set", followFlag: true, want: true},
+		{name: "--follow", followFlag: true, args: []string{"--follow"}, want: false},
+		{name: "-f", followFlag: true, args: []string{"-f"}, want: false},
+		{name: "--follow=false", followFlag: true, args: []string{"--follow=false"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test", Annotations: tt.annotations}
+			if tt.followFlag {
+				cmd.Flags().BoolP("follow", "f", false, "")
+			}
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			saved := noPager
+			noPager = tt.noPager
+			defer func() { noPager = saved }()
+
+			if got := wantsPager(cmd); got != tt.want {
+				t.Errorf("wantsPager() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStreamingCommandsSkipPager guards the commands whose output has to show up while
+// they run.
+func TestStreamingCommandsSkipPager(t *testing.T) {
+	for _, cmd := range []*cobra.Command{dnsCmd, connectCmd, probeCmd, watchCmd, serveCmd, shellCmd, uiCmd} {
+		if wantsPager(cmd) {
+			t.Errorf("%s goes through the pager", cmd.Name())
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// execContainer is the container exec runs the command in.
+var execContainer string
+
+// execCmd runs a command in the pod matching a pattern, like kubectl exec -it.
+var execCmd = &cobra.Command{
+	Use:   "exec [SEARCH_PATTERN] [-- COMMAND...]",
+	Short: "Open a shell, or run COMMAND, in the pod matching [SEARCH_PATTERN], attached to the terminal like kubectl exec -it.",
+	RunE:  runExecFunc(configFlags),
+	// The command owns the terminal.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(execCmd, "pods")
+	execCmd.Flags().StringVarP(&execContainer, "container", "c", "",
+		"Container to run the command in. Defaults to the pod's default container.")
+}
+
+// runExecFunc returns a function that attaches the terminal to COMMAND in the matching pod.
+func runExecFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		command := []string{"sh"}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			if len(args) > dash {
+				command = args[dash:]
+			}
+			args = args[:dash]
+		}
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a pod pattern, for example:\n  kubectl helper exec api\nor:\n  kubectl helper exec api -c app -- cat /etc/resolv.conf")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		// Ctrl+C goes to the command in the pod; this only ends a session without a terminal.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		pod, err := findSinglePod(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		container := execContainer
+		if container == "" {
+			container = defaultContainer(pod)
+		}
+		if err := interactiveExecInPod(ctx, configFlags, clientset, pod, container, command); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("exec in %s/%s failed: %w", pod.Namespace, pod.Name, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/encoding/protowire"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// ProbeInfo holds the result of running one probe of a container from here.
+type ProbeInfo struct {
+	Pod       string
+	Namespace string
+	Container string
+	// Type is liveness, readiness or startup.
+	Type string
+	// Check describes the probe, e.g. "GET :8080/healthz".
+	Check   string
+	OK      bool
+	Result  string
+	Latency time.Duration
+	// Timeout is the probe's timeoutSeconds, after which the kubelet counts it as failed.
+	Timeout time.Duration
+}
+
+// probe command flags.
+var (
+	probeTypes     []string
+	probeContainer string
+)
+
+// grpcHealthStatuses names the statuses of grpc.health.v1.HealthCheckResponse.
+var grpcHealthStatuses = map[uint64]string{0: "UNKNOWN", 1: "SERVING", 2: "NOT_SERVING", 3: "SERVICE_UNKNOWN"}
+
+// probeCmd runs the probes of the matching pods' containers the way the kubelet would.
+var probeCmd = &cobra.Command{
+	Use:   "probe [SEARCH_PATTERN]",
+	Short: "Run the liveness, readiness and startup probes of pods containing [SEARCH_PATTERN] through port-forward or exec and show their result and latency.",
+	RunE:  runProbeFunc(configFlags),
+	// Probes run through exec and port-forwards and can take a while; keep the output unbuffered.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(probeCmd, "pods")
+	probeCmd.Flags().StringSliceVar(&probeTypes, "type", []string{"liveness", "readiness", "startup"},
+		"Probes to run: liveness, readiness, startup.")
+	probeCmd.Flags().StringVarP(&probeContainer, "container", "c", "",
+		"Only run the probes of this container.")
+}
+
+// runProbeFunc returns a function that runs every selected probe once and prints the results.
+func runProbeFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper probe api\nor:\n  kubectl helper probe -n dev api --type readiness")
+		}
+		searchTerm := args[0]
+		for _, t := range probeTypes {
+			if t != "liveness" && t != "readiness" && t != "startup" {
+				return fmt.Errorf("unknown probe type %q, must be one of: liveness, readiness, startup", t)
+			}
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		var results []ProbeInfo
+		for i := range pods {
+			pod := &pods[i]
+			if pod.Status.Phase != corev1.PodRunning {
+				color.New(color.FgYellow).Fprintf(os.Stderr, "Skipping %s/%s: it is %s\n", pod.Namespace, pod.Name, pod.Status.Phase)
+				continue
+			}
+			forwarder := &podForwarder{configFlags: configFlags, clientset: clientset, pod: pod, local: map[int32]uint16{}}
+			for _, c := range pod.Spec.Containers {
+				if probeContainer != "" && c.Name != probeContainer {
+					continue
+				}
+				probes := map[string]*corev1.Probe{"liveness": c.LivenessProbe, "readiness": c.ReadinessProbe, "startup": c.StartupProbe}
+				for _, t := range probeTypes {
+					if probes[t] == nil {
+						continue
+					}
+					result := runProbe(ctx, forwarder, &c, probes[t])
+					result.Pod, result.Namespace, result.Container, result.Type = pod.Name, pod.Namespace, c.Name, t
+					results = append(results, result)
+				}
+			}
+			forwarder.close()
+		}
+		historyMatches = len(results)
+		if len(results) == 0 {
+			fmt.Printf("No probes found in pods matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		printProbeTable(results)
+		return nil
+	}
+}
+
+// runProbe runs one probe of container c with the probe's timeout.
+func runProbe(ctx context.Context, forwarder *podForwarder, c *corev1.Container, probe *corev1.Probe) ProbeInfo {
+	timeout := time.Duration(max(probe.TimeoutSeconds, 1)) * time.Second
+	info := ProbeInfo{Timeout: timeout}
+	// Setting up the port-forward doesn't count against the probe's timeout.
+	forwardCtx := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var check func() (string, error)
+	switch {
+	case probe.HTTPGet != nil:
+		h := probe.HTTPGet
+		port, err := containerPort(c, h.Port)
+		info.Check = fmt.Sprintf("%s :%s%s", strings.ToUpper(string(h.Scheme)), h.Port.String(), h.Path)
+		if h.Scheme == "" {
+			info.Check = fmt.Sprintf("HTTP :%s%s", h.Port.String(), h.Path)
+		}
+		if err != nil {
+			info.Result = err.Error()
+			return info
+		}
+		if h.Host != "" {
+			info.Result = "probes host " + h.Host + ", not the pod; skipped"
+			return info
+		}
+		local, err := forwarder.forward(forwardCtx, port)
+		if err != nil {
+			info.Result = err.Error()
+			return info
+		}
+		check = func() (string, error) { return httpProbe(ctx, local, h) }
+	case probe.TCPSocket != nil:
+		port, err := containerPort(c, probe.TCPSocket.Port)
+		info.Check = "TCP :" + probe.TCPSocket.Port.String()
+		if err != nil {
+			info.Result = err.Error()
+			return info
+		}
+		local, err := forwarder.forward(forwardCtx, port)
+		if err != nil {
+			info.Result = err.Error()
+			return info
+		}
+		check = func() (string, error) { return tcpProbe(ctx, local, port) }
+	case probe.GRPC != nil:
+		info.Check = fmt.Sprintf("gRPC :%d", probe.GRPC.Port)
+		service := ""
+		if probe.GRPC.Service != nil {
+			service = *probe.GRPC.Service
+			info.Check += " " + service
+		}
+		local, err := forwarder.forward(forwardCtx, probe.GRPC.Port)
+		if err != nil {
+			info.Result = err.Error()
+			return info
+		}
+		check = func() (string, error) { return grpcProbe(ctx, local, service) }
+	case probe.Exec != nil:
+		info.Check = "exec " + strings.Join(probe.Exec.Command, " ")
+		check = func() (string, error) {
+			stdout, stderr, err := execInPod(ctx, forwarder.configFlags, forwarder.clientset, forwarder.pod, c.Name, probe.Exec.Command)
+			if err != nil {
+				return "", fmt.Errorf("%w: %s", err, firstLine(strings.TrimSpace(stderr+stdout), ""))
+			}
+			return "exit 0", nil
+		}
+	default:
+		info.Result = "unknown probe handler"
+		return info
+	}
+
+	start := time.Now()
+	result, err := check()
+	info.Latency = time.Since(start)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		info.Result = err.Error()
+		return info
+	}
+	info.OK, info.Result = true, result
+	return info
+}
+
+// containerPort resolves a probe port, by number or by the name of a container port.
+func containerPort(c *corev1.Container, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		return port.IntVal, nil
+	}
+	for _, p := range c.Ports {
+		if p.Name == port.StrVal {
+			return p.ContainerPort, nil
+		}
+	}
+	return 0, fmt.Errorf("container %s has no port named %s", c.Name, port.StrVal)
+}
+
+// httpProbe sends the probe's request to the forwarded local port. Like the kubelet, it
+// passes 200-399 and doesn't follow redirects.
+func httpProbe(ctx context.Context, local uint16, h *corev1.HTTPGetAction) (string, error) {
+	scheme := "http"
+	if h.Scheme == corev1.URISchemeHTTPS {
+		scheme = "https"
+	}
+	path := h.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://127.0.0.1:%d%s", scheme, local, path), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "kube-probe/kubectl-helper")
+	for _, header := range h.HTTPHeaders {
+		if strings.EqualFold(header.Name, "Host") {
+			req.Host = header.Value
+			continue
+		}
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	client := &http.Client{
+		// The kubelet doesn't verify the certificates of HTTPS probes either.
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode), nil
+}
+
+// tcpProbe connects to the pod port forwarded to local. The forwarded connection is closed
+// right away when the pod refuses it, so a connection still open after a moment counts as accepted.
+func tcpProbe(ctx context.Context, local uint16, port int32) (string, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", fmt.Sprintf("127.0.0.1:%d", local))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(500 * time.Millisecond)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetReadDeadline(deadline)
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	if err == nil || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "connected", nil
+	}
+	return "", fmt.Errorf("connection closed, nothing listening on %d?", port)
+}
+
+// grpcProbe calls grpc.health.v1.Health/Check over plaintext HTTP/2, like the kubelet.
+func grpcProbe(ctx context.Context, local uint16, service string) (string, error) {
+	var msg []byte
+	if service != "" {
+		msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+		msg = protowire.AppendString(msg, service)
+	}
+	// gRPC frames a message with a compression flag and its length.
+	body := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(msg)))
+	body = append(body, msg...)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("http://127.0.0.1:%d/grpc.health.v1.Health/Check", local), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	// Errors without a message come as headers only, the rest in the trailers.
+	status, message := resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if status != "0" {
+		return "", fmt.Errorf("grpc-status %s %s", status, message)
+	}
+
+	var serving uint64
+	if len(data) >= 5 {
+		msg := data[5:]
+		for len(msg) > 0 {
+			num, typ, n := protowire.ConsumeTag(msg)
+			if n < 0 {
+				break
+			}
+			msg = msg[n:]
+			if num == 1 && typ == protowire.VarintType {
+				v, n := protowire.ConsumeVarint(msg)
+				if n < 0 {
+					break
+				}
+				serving, msg = v, msg[n:]
+				continue
+			}
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				break
+			}
+			msg = msg[n:]
+		}
+	}
+	name, ok := grpcHealthStatuses[serving]
+	if !ok {
+		name = fmt.Sprintf("status %d", serving)
+	}
+	if serving != 1 {
+		return "", errors.New(name)
+	}
+	return name, nil
+}
+
+// podForwarder forwards local ports to the ports of one pod, each at most once.
+type podForwarder struct {
+	configFlags *genericclioptions.ConfigFlags
+	clientset   kubernetes.Interface
+	pod         *corev1.Pod
+	// local maps the forwarded pod ports to their local port.
+	local map[int32]uint16
+	stops []chan struct{}
+}
+
+// forward returns the local port forwarded to port of the pod, starting the forward if needed.
+func (f *podForwarder) forward(ctx context.Context, port int32) (uint16, error) {
+	if local, ok := f.local[port]; ok {
+		return local, nil
+	}
+	restConfig, err := f.configFlags.ToRESTConfig()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(restConfig)
+	if err != nil {
+		return 0, err
+	}
+	req := f.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").Namespace(f.pod.Namespace).Name(f.pod.Name).SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	stop, ready := make(chan struct{}), make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{fmt.Sprintf("0:%d", port)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return 0, err
+	}
+	failed := make(chan error, 1)
+	go func() {
+		failed <- fw.ForwardPorts()
+	}()
+	select {
+	case <-ready:
+	case err := <-failed:
+		return 0, fmt.Errorf("failed to port-forward to %s: %w", f.pod.Name, err)
+	case <-ctx.Done():
+		close(stop)
+		return 0, ctx.Err()
+	}
+	f.stops = append(f.stops, stop)
+
+	ports, err := fw.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return 0, fmt.Errorf("failed to port-forward to %s: %v", f.pod.Name, err)
+	}
+	f.local[port] = ports[0].Local
+	return ports[0].Local, nil
+}
+
+// close stops every forward.
+func (f *podForwarder) close() {
+	for _, stop := range f.stops {
+		close(stop)
+	}
+	f.stops = nil
+}
+
+// printProbeTable prints a row per probe: failures red, passes close to their timeout yellow.
+func printProbeTable(results []ProbeInfo) {
+	okColor := color.New(color.FgGreen)
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("POD", "NAMESPACE", "CONTAINER", "PROBE", "CHECK", "RESULT", "LATENCY", "TIMEOUT")
+	for _, r := range results {
+		latency := "-"
+		if r.Latency > 0 {
+			latency = r.Latency.Round(time.Millisecond).String()
+		}
+		t.addRow(r.Pod, r.Namespace, r.Container, r.Type, r.Check, r.Result, latency, r.Timeout.String())
+
+		switch {
+		case !r.OK:
+			t.colorCell(5, errorColor)
+		case r.Latency > r.Timeout*8/10:
+			t.colorCell(5, okColor)
+			t.colorCell(6, warnColor)
+		default:
+			t.colorCell(5, okColor)
+		}
+	}
+	t.print(color.Output)
+}
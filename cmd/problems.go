@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// ProblemInfo holds why one pod is considered unhealthy.
+type ProblemInfo struct {
+	Pod       string
+	Namespace string
+	Phase     string
+	Priority  string
+	Problem   string
+}
+
+// problemsCmd lists the matching pods that aren't healthy.
+var problemsCmd = &cobra.Command{
+	Use:   "problems [SEARCH_PATTERN]",
+	Short: "List pods containing [SEARCH_PATTERN] (all pods if omitted) that are pending, failing or not ready.",
+	RunE:  runProblemsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(problemsCmd, "pods")
+}
+
+// runProblemsFunc returns a function that prints one row per unhealthy pod.
+func runProblemsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		// Without a pattern every pod of the target namespaces is checked.
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+
+		var problems []ProblemInfo
+		for i := range pods {
+			pod := &pods[i]
+			problem := podProblem(pod)
+			if problem == "" {
+				continue
+			}
+			priority := fmt.Sprintf("%d", podPriority(pod))
+			if pod.Spec.PriorityClassName != "" {
+				priority = fmt.Sprintf("%s (%s)", pod.Spec.PriorityClassName, priority)
+			}
+			problems = append(problems, ProblemInfo{
+				Pod:       pod.Name,
+				Namespace: pod.Namespace,
+				Phase:     string(pod.Status.Phase),
+				Priority:  priority,
+				Problem:   problem,
+			})
+		}
+
+		if len(problems) == 0 {
+			color.New(color.FgGreen, color.Bold).Printf("No problems found in %d pod(s).\n", len(pods))
+			return nil
+		}
+		printProblemTable(problems)
+		return nil
+	}
+}
+
+// podPriority returns the resolved priority of pod, 0 if none was set.
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+// podProblem describes what is wrong with pod, or returns "" for a healthy or completed pod.
+func podProblem(pod *corev1.Pod) string {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return ""
+	case corev1.PodFailed:
+		if pod.Status.Reason != "" {
+			return fmt.Sprintf("failed: %s", pod.Status.Reason)
+		}
+		return "failed"
+	}
+
+	if pod.Status.Phase == corev1.PodPending {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != corev1.PodScheduled || cond.Status != corev1.ConditionFalse {
+				continue
+			}
+			// The scheduler nominates a node once it decided to preempt lower-priority
+			// pods there; the pod stays Pending until they are gone.
+			if pod.Status.NominatedNodeName != "" {
+				return fmt.Sprintf("waiting on preemption of lower-priority pods on %s", pod.Status.NominatedNodeName)
+			}
+			return fmt.Sprintf("unschedulable: %s", cond.Message)
+		}
+		if _, _, blocking := initContainerProgress(pod); blocking != "" {
+			return "init " + blocking
+		}
+	}
+
+	for _, st := range pod.Status.ContainerStatuses {
+		if st.State.Waiting != nil && st.State.Waiting.Reason != "" && st.State.Waiting.Reason != "ContainerCreating" {
+			return fmt.Sprintf("%s (container %s)", st.State.Waiting.Reason, st.Name)
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return "terminating"
+	}
+	if pod.Status.Phase == corev1.PodRunning && !isPodReady(pod) {
+		return "running but not ready"
+	}
+	if pod.Status.Phase == corev1.PodPending {
+		return "pending"
+	}
+	return ""
+}
+
+// printProblemTable prints the unhealthy pods, with preemption waits in yellow and the rest in red.
+func printProblemTable(problems []ProblemInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed)
+
+	fmt.Println()
+	headerColor.Printf("%-40s %-20s %-10s %-30s %-50s\n", "NAME", "NAMESPACE", "PHASE", "PRIORITY", "PROBLEM")
+	line := strings.Repeat("-", 150)
+	lineColor.Println(line)
+
+	for _, p := range problems {
+		fmt.Printf("%-40s %-20s %-10s %-30s ", p.Pod, p.Namespace, p.Phase, p.Priority)
+		if strings.HasPrefix(p.Problem, "waiting on preemption") {
+			warnColor.Println(p.Problem)
+		} else {
+			errorColor.Println(p.Problem)
+		}
+	}
+	fmt.Println()
+}
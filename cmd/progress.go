@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// spinnerFrames are drawn in turn in front of the progress line.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// progress keeps a single self-overwriting status line on stderr while a long listing
+// runs, so users can tell a slow search from a hung one. It only draws when stdout is
+// a terminal; piped output stays untouched.
+type progress struct {
+	mu         sync.Mutex
+	enabled    bool
+	namespaces map[string]bool
+	pods       int
+	matches    int
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newProgress creates a progress line; call start to begin drawing it.
+func newProgress() *progress {
+	return &progress{
+		enabled:    stdoutIsTerminal(),
+		namespaces: map[string]bool{},
+		done:       make(chan struct{}),
+	}
+}
+
+// start redraws the progress line in the background until stop is called.
+func (p *progress) start() {
+	if !p.enabled {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-p.done:
+				// Clear the line so the results start on a clean row.
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				fmt.Fprintf(os.Stderr, "\r\033[K%s %d namespaces scanned, %d pods seen, %d matches so far",
+					spinnerFrames[frame%len(spinnerFrames)], len(p.namespaces), p.pods, p.matches)
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// seen records a pod of namespace that has been looked at, and whether it matched.
+func (p *progress) seen(namespace string, matched bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.namespaces[namespace] = true
+	p.pods++
+	if matched {
+		p.matches++
+	}
+}
+
+// stop removes the progress line and waits for the drawing goroutine to exit.
+func (p *progress) stop() {
+	if !p.enabled {
+		return
+	}
+	close(p.done)
+	p.wg.Wait()
+}
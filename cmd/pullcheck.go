@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PullCheckInfo holds the registry credential lookup for one container image.
+type PullCheckInfo struct {
+	Pod        string
+	Namespace  string
+	Container  string
+	Image      string
+	Registry   string
+	Credential string
+}
+
+// pullSecret is a parsed imagePullSecret: the registries it holds credentials for,
+// or the problem that makes it unusable.
+type pullSecret struct {
+	Name       string
+	Registries map[string]bool
+	Problem    string
+}
+
+// dockerConfigJSON is the layout of a kubernetes.io/dockerconfigjson secret.
+type dockerConfigJSON struct {
+	Auths map[string]json.RawMessage `json:"auths"`
+}
+
+// pullcheckCmd checks whether the matching pods can pull their images.
+var pullcheckCmd = &cobra.Command{
+	Use:   "pullcheck [SEARCH_PATTERN]",
+	Short: "Verify the imagePullSecrets of pods containing [SEARCH_PATTERN] cover the registries of their images.",
+	RunE:  runPullcheckFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(pullcheckCmd, "pods")
+}
+
+// runPullcheckFunc returns a function that resolves every image of the matching pods to a
+// registry credential and surfaces recent image pull failures.
+func runPullcheckFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper pullcheck api\nor:\n  kubectl helper pullcheck -n dev api")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// Secrets are shared by many replicas, so each one is only fetched once.
+		secrets := map[string]*pullSecret{}
+		var rows []PullCheckInfo
+		var problems []string
+
+		for i := range pods {
+			pod := &pods[i]
+			podSecrets, err := podPullSecrets(ctx, clientset, pod, secrets)
+			if err != nil {
+				return err
+			}
+			for _, s := range podSecrets {
+				if s.Problem != "" {
+					problems = append(problems, fmt.Sprintf("%s/%s: imagePullSecret %s %s", pod.Namespace, pod.Name, s.Name, s.Problem))
+				}
+			}
+
+			var containers []corev1.Container
+			containers = append(containers, pod.Spec.InitContainers...)
+			containers = append(containers, pod.Spec.Containers...)
+			for _, c := range containers {
+				registry := imageRegistry(c.Image)
+				row := PullCheckInfo{
+					Pod:        pod.Name,
+					Namespace:  pod.Namespace,
+					Container:  c.Name,
+					Image:      c.Image,
+					Registry:   registry,
+					Credential: "<none>",
+				}
+				for _, s := range podSecrets {
+					if s.Registries[registry] {
+						row.Credential = s.Name
+						break
+					}
+				}
+				rows = append(rows, row)
+			}
+
+			events, err := imagePullEvents(ctx, clientset, pod)
+			if err != nil {
+				return err
+			}
+			problems = append(problems, events...)
+		}
+
+		printPullCheckTable(rows)
+		printPullCheckProblems(problems)
+		return nil
+	}
+}
+
+// podPullSecrets returns the parsed imagePullSecrets of the pod, including the ones it
+// inherits from its service account. cache is keyed by namespace/name.
+func podPullSecrets(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, cache map[string]*pullSecret) ([]*pullSecret, error) {
+	refs := append([]corev1.LocalObjectReference{}, pod.Spec.ImagePullSecrets...)
+	if pod.Spec.ServiceAccountName != "" {
+		sa, err := clientset.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, pod.Spec.ServiceAccountName, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to retrieve serviceaccount %s: %w", pod.Spec.ServiceAccountName, err)
+		}
+		if err == nil {
+			refs = append(refs, sa.ImagePullSecrets...)
+		}
+	}
+
+	var result []*pullSecret
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if seen[ref.Name] {
+			continue
+		}
+		seen[ref.Name] = true
+
+		key := pod.Namespace + "/" + ref.Name
+		if s, ok := cache[key]; ok {
+			result = append(result, s)
+			continue
+		}
+
+		s := &pullSecret{Name: ref.Name, Registries: map[string]bool{}}
+		secret, err := clientset.CoreV1().Secrets(pod.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			s.Problem = "does not exist"
+		case apierrors.IsForbidden(err):
+			s.Problem = "can't be read (forbidden)"
+		case err != nil:
+			return nil, fmt.Errorf("failed to retrieve secret %s: %w", ref.Name, err)
+		default:
+			parseDockerSecret(secret, s)
+		}
+		cache[key] = s
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// parseDockerSecret fills in the registries of a dockerconfigjson (or legacy dockercfg) secret.
+func parseDockerSecret(secret *corev1.Secret, s *pullSecret) {
+	var auths map[string]json.RawMessage
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		var cfg dockerConfigJSON
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &cfg); err != nil {
+			s.Problem = fmt.Sprintf("has an invalid %s: %v", corev1.DockerConfigJsonKey, err)
+			return
+		}
+		auths = cfg.Auths
+	case corev1.SecretTypeDockercfg:
+		if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+			s.Problem = fmt.Sprintf("has an invalid %s: %v", corev1.DockerConfigKey, err)
+			return
+		}
+	default:
+		s.Problem = fmt.Sprintf("has type %s instead of %s", secret.Type, corev1.SecretTypeDockerConfigJson)
+		return
+	}
+
+	if len(auths) == 0 {
+		s.Problem = "contains no registry credentials"
+		return
+	}
+	for server := range auths {
+		s.Registries[normalizeRegistry(server)] = true
+	}
+}
+
+// imageRegistry returns the registry host an image is pulled from, e.g. "docker.io"
+// for "nginx:1.25" and "ghcr.io" for "ghcr.io/org/app@sha256:...".
+func imageRegistry(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	// Like Docker, the first path segment is only a registry if it looks like a host.
+	if !found || (!strings.ContainsAny(first, ".:") && first != "localhost") {
+		return "docker.io"
+	}
+	return normalizeRegistry(first)
+}
+
+// normalizeRegistry turns a credential key like "https://index.docker.io/v1/" into the
+// bare registry host used by imageRegistry.
+func normalizeRegistry(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server, _, _ = strings.Cut(server, "/")
+	switch server {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return "docker.io"
+	}
+	return server
+}
+
+// podEvents returns the events recorded for pod, most recent first.
+func podEvents(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) ([]corev1.Event, error) {
+	events, err := clientset.CoreV1().Events(pod.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+			fields.OneTermEqualSelector("involvedObject.name", pod.Name),
+		).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve events: %w", err)
+	}
+
+	sort.Slice(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).After(eventTime(&events.Items[j]))
+	})
+	return events.Items, nil
+}
+
+// imagePullEvents returns the recent image pull failures recorded for pod.
+func imagePullEvents(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) ([]string, error) {
+	events, err := podEvents(ctx, clientset, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, ev := range events {
+		if ev.Type != corev1.EventTypeWarning {
+			continue
+		}
+		msg := strings.ToLower(ev.Message)
+		if !strings.Contains(msg, "pull") && !strings.Contains(ev.Reason, "ImagePull") {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s/%s: %s %s ago (x%d): %s", pod.Namespace, pod.Name,
+			ev.Reason, duration.HumanDuration(time.Since(eventTime(&ev))), max(ev.Count, 1), ev.Message))
+	}
+	return messages, nil
+}
+
+// eventTime returns the most meaningful timestamp of an event.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
+// printPullCheckTable prints which credential covers every image, in red when none does.
+func printPullCheckTable(rows []PullCheckInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	missingColor := color.New(color.FgYellow)
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-20s %-50s %-25s %-25s\n", "POD", "NAMESPACE", "CONTAINER", "IMAGE", "REGISTRY", "CREDENTIAL")
+	line := strings.Repeat("-", 175)
+	lineColor.Println(line)
+
+	for _, r := range rows {
+		fmt.Printf("%-35s %-15s %-20s %-50s %-25s ", r.Pod, r.Namespace, r.Container, r.Image, r.Registry)
+		if r.Credential == "<none>" {
+			// Fine for public images, but the first suspect when a pull fails.
+			missingColor.Printf("%-25s\n", r.Credential)
+		} else {
+			fmt.Printf("%-25s\n", r.Credential)
+		}
+	}
+	fmt.Println()
+}
+
+// printPullCheckProblems prints the broken secrets and pull failures that were found.
+func printPullCheckProblems(problems []string) {
+	if len(problems) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("No imagePullSecret problems or image pull failures found.")
+		fmt.Println()
+		return
+	}
+	problemColor := color.New(color.FgRed)
+	for _, p := range problems {
+		problemColor.Println(p)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PvcInfo holds one PVC together with its volume and the pods mounting it.
+type PvcInfo struct {
+	Name         string
+	Namespace    string
+	Status       string
+	PV           string
+	StorageClass string
+	Capacity     string
+	Used         string
+	Pods         []string
+}
+
+// kubeletStatsSummary is the part of the kubelet's /stats/summary response holding volume usage.
+type kubeletStatsSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			UsedBytes *int64 `json:"usedBytes"`
+			PVCRef    *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// pvcCmd lists PVCs with their volumes, mounting pods and usage.
+var pvcCmd = &cobra.Command{
+	Use:   "pvc [SEARCH_PATTERN]",
+	Short: "List PVCs containing [SEARCH_PATTERN] (all if omitted) with their PV, storage class, capacity, usage and pods.",
+	RunE:  runPvcFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(pvcCmd, "PVCs")
+}
+
+// runPvcFunc returns a function that maps the matching PVCs to the pods mounting them and
+// flags claims that are unbound or not mounted by any pod.
+func runPvcFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pvcs, err := listAcrossNamespaces(configFlags, func(ns string) ([]corev1.PersistentVolumeClaim, error) {
+			list, err := clientset.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve persistentvolumeclaims: %w", err)
+		}
+
+		var matching []corev1.PersistentVolumeClaim
+		for _, pvc := range pvcs {
+			if matchesPattern(pvc.Name, searchTerm) {
+				matching = append(matching, pvc)
+			}
+		}
+		if len(matching) == 0 {
+			fmt.Printf("No persistentvolumeclaims found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		pods, err := findPods(ctx, configFlags, clientset, "")
+		if err != nil {
+			return err
+		}
+
+		// Pods mounting each claim, keyed by namespace/claim, and the nodes to ask for usage.
+		mountedBy := map[string][]string{}
+		nodes := map[string]bool{}
+		for _, pod := range pods {
+			for _, claim := range podClaimNames(&pod) {
+				key := pod.Namespace + "/" + claim
+				mountedBy[key] = append(mountedBy[key], pod.Name)
+				if pod.Spec.NodeName != "" {
+					nodes[pod.Spec.NodeName] = true
+				}
+			}
+		}
+
+		usage := volumeUsage(ctx, clientset, nodes)
+
+		var rows []PvcInfo
+		for _, pvc := range matching {
+			key := pvc.Namespace + "/" + pvc.Name
+			row := PvcInfo{
+				Name:         pvc.Name,
+				Namespace:    pvc.Namespace,
+				Status:       string(pvc.Status.Phase),
+				PV:           "<none>",
+				StorageClass: "<none>",
+				Capacity:     "<none>",
+				Used:         "<unknown>",
+				Pods:         mountedBy[key],
+			}
+			if pvc.Spec.VolumeName != "" {
+				row.PV = pvc.Spec.VolumeName
+			}
+			if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+				row.StorageClass = *pvc.Spec.StorageClassName
+			}
+			if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+				row.Capacity = capacity.String()
+				if used, ok := usage[key]; ok && capacity.Value() > 0 {
+					row.Used = fmt.Sprintf("%s (%d%%)", formatBytes(used), used*100/capacity.Value())
+				}
+			}
+
+			switch {
+			case pvc.Status.Phase != corev1.ClaimBound:
+				row.Status += " (unbound)"
+			case len(row.Pods) == 0:
+				row.Status += " (orphaned)"
+			}
+			rows = append(rows, row)
+		}
+
+		sort.Slice(rows, func(i, j int) bool {
+			if rows[i].Namespace != rows[j].Namespace {
+				return rows[i].Namespace < rows[j].Namespace
+			}
+			return rows[i].Name < rows[j].Name
+		})
+		printPvcTable(rows)
+		return nil
+	}
+}
+
+// podClaimNames returns the PVCs mounted by pod, including the claims created for its
+// generic ephemeral volumes ("<pod>-<volume>").
+func podClaimNames(pod *corev1.Pod) []string {
+	var claims []string
+	for _, vol := range pod.Spec.Volumes {
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			claims = append(claims, vol.PersistentVolumeClaim.ClaimName)
+		case vol.Ephemeral != nil:
+			claims = append(claims, pod.Name+"-"+vol.Name)
+		}
+	}
+	return claims
+}
+
+// volumeUsage asks the kubelet of every node for the bytes used by each PVC, keyed by
+// namespace/claim. Nodes whose stats can't be read (e.g. no nodes/proxy permission) are
+// skipped, leaving the usage of their claims unknown.
+func volumeUsage(ctx context.Context, clientset kubernetes.Interface, nodes map[string]bool) map[string]int64 {
+	usage := map[string]int64{}
+	for node := range nodes {
+		raw, err := clientset.CoreV1().RESTClient().Get().
+			Resource("nodes").Name(node).SubResource("proxy").Suffix("stats/summary").
+			DoRaw(ctx)
+		if err != nil {
+			continue
+		}
+		var summary kubeletStatsSummary
+		if err := json.Unmarshal(raw, &summary); err != nil {
+			continue
+		}
+		for _, pod := range summary.Pods {
+			for _, vol := range pod.Volumes {
+				if vol.PVCRef == nil || vol.UsedBytes == nil {
+					continue
+				}
+				usage[vol.PVCRef.Namespace+"/"+vol.PVCRef.Name] = *vol.UsedBytes
+			}
+		}
+	}
+	return usage
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5Gi".
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d", b)
+	}
+	value, exp := float64(b)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ci", value, "KMGTP"[exp])
+}
+
+// printPvcTable prints the PVCs, flagging unbound claims in red and orphaned ones in yellow.
+func printPvcTable(rows []PvcInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	unboundColor := color.New(color.FgRed)
+	orphanedColor := color.New(color.FgYellow)
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-18s %-42s %-15s %-10s %-16s %-40s\n",
+		"PVC", "NAMESPACE", "STATUS", "PV", "STORAGE CLASS", "CAPACITY", "USED", "PODS")
+	line := strings.Repeat("-", 200)
+	lineColor.Println(line)
+
+	for _, r := range rows {
+		fmt.Printf("%-35s %-15s ", r.Name, r.Namespace)
+		switch {
+		case strings.HasSuffix(r.Status, "(unbound)"):
+			unboundColor.Printf("%-18s", r.Status)
+		case strings.HasSuffix(r.Status, "(orphaned)"):
+			orphanedColor.Printf("%-18s", r.Status)
+		default:
+			fmt.Printf("%-18s", r.Status)
+		}
+		pods := "<none>"
+		if len(r.Pods) > 0 {
+			pods = strings.Join(r.Pods, ",")
+		}
+		fmt.Printf(" %-42s %-15s %-10s %-16s %-40s\n", r.PV, r.StorageClass, r.Capacity, r.Used, pods)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// qpsFlag and burstFlag set the client-side rate limit of every API client.
+var (
+	qpsFlag   float32
+	burstFlag int
+)
+
+// throttleHintThreshold is the total client-side throttling after which a hint to raise
+// --qps/--burst is printed.
+const throttleHintThreshold = time.Second
+
+func init() {
+	RootCmd.PersistentFlags().Float32Var(&qpsFlag, "qps", rest.DefaultQPS,
+		"Maximum API requests per second. Raise it when scanning big clusters.")
+	RootCmd.PersistentFlags().IntVar(&burstFlag, "burst", rest.DefaultBurst,
+		"Maximum burst of API requests above --qps.")
+}
+
+// throttleRecorder is a rate limiter that adds up how long requests waited for it.
+type throttleRecorder struct {
+	flowcontrol.RateLimiter
+
+	mu     sync.Mutex
+	waited time.Duration
+}
+
+// clientThrottle is shared by every client, so --qps limits the whole command.
+var clientThrottle *throttleRecorder
+
+func (t *throttleRecorder) Accept() {
+	start := time.Now()
+	t.RateLimiter.Accept()
+	t.record(time.Since(start))
+}
+
+func (t *throttleRecorder) Wait(ctx context.Context) error {
+	start := time.Now()
+	err := t.RateLimiter.Wait(ctx)
+	t.record(time.Since(start))
+	return err
+}
+
+func (t *throttleRecorder) record(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.waited += d
+}
+
+// setupRateLimit applies --qps and --burst to the clients built from configFlags.
+func setupRateLimit() error {
+	if qpsFlag <= 0 || burstFlag < 1 {
+		return fmt.Errorf("--qps must be positive and --burst at least 1")
+	}
+	clientThrottle = &throttleRecorder{RateLimiter: flowcontrol.NewTokenBucketRateLimiter(qpsFlag, burstFlag)}
+	restConfigWrappers = append(restConfigWrappers, func(c *rest.Config) {
+		c.QPS, c.Burst = qpsFlag, burstFlag
+		c.RateLimiter = clientThrottle
+	})
+	return nil
+}
+
+// printThrottleHint suggests higher limits when requests spent noticeable time
+// waiting for the client-side rate limiter.
+func printThrottleHint() {
+	if clientThrottle == nil {
+		return
+	}
+	clientThrottle.mu.Lock()
+	waited := clientThrottle.waited
+	clientThrottle.mu.Unlock()
+	if waited < throttleHintThreshold {
+		return
+	}
+	color.New(color.FgYellow).Fprintf(os.Stderr,
+		"Requests waited %s in total for client-side throttling (--qps %g, --burst %d); raise them to speed this up, e.g. --qps 50 --burst 100\n",
+		waited.Round(100*time.Millisecond), qpsFlag, burstFlag)
+}
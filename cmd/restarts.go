@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// RestartInfo holds how often one container restarted, in total and within the window.
+type RestartInfo struct {
+	Pod       string
+	Namespace string
+	Container string
+	Restarts  int32
+	// InWindow is the number of restarts within the window, estimated from Started events
+	// where the container status alone can't tell.
+	InWindow int32
+	// Rate is InWindow per hour of the window, or of the pod's life when it is younger.
+	Rate        float64
+	LastRestart time.Time
+	LastReason  string
+}
+
+// restarts command flags.
+var (
+	restartsWindow    time.Duration
+	restartsThreshold int32
+	restartsAll       bool
+)
+
+// restartsCmd finds containers restarting repeatedly within a time window.
+var restartsCmd = &cobra.Command{
+	Use:   "restarts [SEARCH_PATTERN]",
+	Short: "Show containers of pods containing [SEARCH_PATTERN] that restarted within --window, sorted by restart rate.",
+	RunE:  runRestartsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(restartsCmd, "pods")
+	restartsCmd.Flags().DurationVar(&restartsWindow, "window", time.Hour,
+		"Time window to count restarts in. Events older than about an hour are usually gone, so longer windows rely on the container status.")
+	restartsCmd.Flags().Int32Var(&restartsThreshold, "threshold", 3,
+		"Restarts within the window that make a restart storm rather than a blip.")
+	restartsCmd.Flags().BoolVar(&restartsAll, "all", false,
+		"Also show containers whose restarts all happened before the window.")
+}
+
+// runRestartsFunc returns a function that prints the restarting containers, fastest first.
+func runRestartsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper restarts api\nor:\n  kubectl helper restarts -A --window 30m \"\"")
+		}
+		searchTerm := args[0]
+		if restartsWindow <= 0 {
+			return fmt.Errorf("--window must be positive")
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// Every container start is a Started event; without access to events the
+		// container status is all there is.
+		events, err := listAcrossNamespaces(configFlags, func(ns string) ([]corev1.Event, error) {
+			list, err := clientset.CoreV1().Events(ns).List(ctx, metav1.ListOptions{
+				FieldSelector: fields.AndSelectors(
+					fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+					fields.OneTermEqualSelector("reason", "Started"),
+				).String(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil && !isForbidden(err) {
+			return fmt.Errorf("failed to retrieve events: %w", err)
+		}
+		starts := map[string][]corev1.Event{}
+		for _, ev := range events {
+			key := string(ev.InvolvedObject.UID) + "/" + fieldPathContainer(ev.InvolvedObject.FieldPath)
+			starts[key] = append(starts[key], ev)
+		}
+
+		now := time.Now()
+		since := now.Add(-restartsWindow)
+		var infos []RestartInfo
+		for i := range pods {
+			pod := &pods[i]
+			statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+			for _, st := range statuses {
+				if st.RestartCount == 0 {
+					continue
+				}
+				info := RestartInfo{
+					Pod:       pod.Name,
+					Namespace: pod.Namespace,
+					Container: st.Name,
+					Restarts:  st.RestartCount,
+					InWindow:  restartsSince(pod, st, starts[string(pod.UID)+"/"+st.Name], since),
+				}
+				if term := st.LastTerminationState.Terminated; term != nil {
+					info.LastRestart = term.FinishedAt.Time
+					info.LastReason = term.Reason
+					if term.ExitCode != 0 {
+						info.LastReason = fmt.Sprintf("%s (exit %d)", term.Reason, term.ExitCode)
+					}
+				}
+				if info.InWindow == 0 && !restartsAll {
+					continue
+				}
+				span := restartsWindow
+				if age := now.Sub(pod.CreationTimestamp.Time); age > 0 && age < span {
+					span = age
+				}
+				info.Rate = float64(info.InWindow) / span.Hours()
+				infos = append(infos, info)
+			}
+		}
+		historyMatches = len(infos)
+		if len(infos) == 0 {
+			fmt.Printf("No containers restarted within %s in pods matching the pattern: %s\n", restartsWindow, searchTerm)
+			return nil
+		}
+
+		sort.SliceStable(infos, func(i, j int) bool {
+			if infos[i].Rate != infos[j].Rate {
+				return infos[i].Rate > infos[j].Rate
+			}
+			return infos[i].Restarts > infos[j].Restarts
+		})
+		printRestartTable(infos)
+		return nil
+	}
+}
+
+// restartsSince estimates how often the container restarted since the given time. All
+// restarts of a pod created since then count; otherwise Started events do, spread evenly
+// over the time between their first and last occurrence when only part of it is recent.
+func restartsSince(pod *corev1.Pod, st corev1.ContainerStatus, starts []corev1.Event, since time.Time) int32 {
+	if pod.CreationTimestamp.Time.After(since) {
+		return st.RestartCount
+	}
+	var n int32
+	for _, ev := range starts {
+		first, last, count := ev.FirstTimestamp.Time, eventTime(&ev), max(ev.Count, 1)
+		switch {
+		case last.Before(since):
+		case first.IsZero() || !first.Before(since) || !last.After(first):
+			n += count
+		default:
+			n += int32(float64(count) * last.Sub(since).Seconds() / last.Sub(first).Seconds())
+		}
+	}
+	if term := st.LastTerminationState.Terminated; term != nil && term.FinishedAt.Time.After(since) {
+		n = max(n, 1)
+	}
+	return min(n, st.RestartCount)
+}
+
+// fieldPathContainer returns the container name of an event field path such as
+// "spec.containers{app}".
+func fieldPathContainer(fieldPath string) string {
+	_, rest, ok := strings.Cut(fieldPath, "{")
+	if !ok {
+		return ""
+	}
+	name, _, _ := strings.Cut(rest, "}")
+	return name
+}
+
+// printRestartTable prints a row per container; storms are red, blips yellow.
+func printRestartTable(infos []RestartInfo) {
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("POD", "NAMESPACE", "CONTAINER", "RESTARTS", "IN WINDOW", "RATE", "LAST RESTART", "LAST REASON", "STATUS")
+	for _, r := range infos {
+		last, reason, status := "-", r.LastReason, "before window"
+		if !r.LastRestart.IsZero() {
+			last = duration.HumanDuration(time.Since(r.LastRestart)) + " ago"
+		}
+		if reason == "" {
+			reason = "-"
+		}
+		switch {
+		case r.InWindow >= restartsThreshold:
+			status = "storm"
+		case r.InWindow > 0:
+			status = "blip"
+		}
+		t.addRow(r.Pod, r.Namespace, r.Container, fmt.Sprintf("%d", r.Restarts), fmt.Sprintf("%d", r.InWindow),
+			fmt.Sprintf("%.1f/h", r.Rate), last, reason, status)
+
+		switch status {
+		case "storm":
+			t.colorCell(4, errorColor)
+			t.colorCell(8, errorColor)
+		case "blip":
+			t.colorCell(8, warnColor)
+		}
+	}
+	t.print(color.Output)
+}
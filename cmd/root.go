@@ -13,15 +13,102 @@ var RootCmd = &cobra.Command{
 	Hidden: true,     // böylece kubectl normalde listemez, sadece plugin çağırır
 	Short:  "Helper commands for kubectl",
 	Long:   `Helper commands for kubectl operations.`,
+	// Hataları cobra değil Execute yazdırsın (--error-format için)
+	SilenceErrors: true,
+	// Config dosyasını her alt komuttan önce okuyoruz
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch errorFormat {
+		case "text":
+		case "json":
+			// JSON çıktısı usage metniyle karışmasın
+			cmd.SilenceUsage = true
+		default:
+			return fmt.Errorf("unknown error format %q, must be one of: text, json", errorFormat)
+		}
+		if err := loadConfig(); err != nil {
+			return err
+		}
+		// shell aynı süreçte birden çok komut çalıştırıyor, önceki komutun ayarları kalmasın
+		restConfigWrappers = nil
+		// -v ve --debug client oluşturulmadan önce ayarlanmalı
+		if err := setupLogging(); err != nil {
+			return err
+		}
+		if err := setupRateLimit(); err != nil {
+			return err
+		}
+		// Uzun çıktılar $PAGER ile gösterilsin
+		return startPager(cmd)
+	},
 }
 
 func Execute() {
-	// ip komutunu ekliyoruz
-	RootCmd.AddCommand(ipCmd)
+	addCommands()
 
 	// root bir iş yapmasın sadece alt komutları çalıştırsın
-	if err := RootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	cmd, err := RootCmd.ExecuteC()
+	finishCommand(cmd, os.Args[1:], err)
+	if err != nil {
 		os.Exit(1)
 	}
 }
+
+// addCommands alt komutları root'a ekler, testler de komut ağacını buradan kuruyor
+func addCommands() {
+	// ip komutunu ekliyoruz
+	RootCmd.AddCommand(ipCmd)
+	RootCmd.AddCommand(stsCmd)
+	RootCmd.AddCommand(dsCmd)
+	RootCmd.AddCommand(svcCmd)
+	RootCmd.AddCommand(logsCmd)
+	RootCmd.AddCommand(diffCmd)
+	RootCmd.AddCommand(spreadCmd)
+	RootCmd.AddCommand(containersCmd)
+	RootCmd.AddCommand(exitsCmd)
+	RootCmd.AddCommand(pullcheckCmd)
+	RootCmd.AddCommand(imagesCmd)
+	RootCmd.AddCommand(problemsCmd)
+	RootCmd.AddCommand(schedCmd)
+	RootCmd.AddCommand(colocationCmd)
+	RootCmd.AddCommand(pvcCmd)
+	RootCmd.AddCommand(saCmd)
+	RootCmd.AddCommand(historyCmd)
+	RootCmd.AddCommand(uiCmd)
+	RootCmd.AddCommand(dnsCmd)
+	RootCmd.AddCommand(connectCmd)
+	RootCmd.AddCommand(sniffCmd)
+	RootCmd.AddCommand(serveCmd)
+	RootCmd.AddCommand(watchCmd)
+	RootCmd.AddCommand(versionCmd)
+	RootCmd.AddCommand(scaleCmd)
+	RootCmd.AddCommand(drainCmd)
+	RootCmd.AddCommand(uncordonCmd)
+	RootCmd.AddCommand(nodehealthCmd)
+	RootCmd.AddCommand(leaderCmd)
+	RootCmd.AddCommand(daemonCmd)
+	RootCmd.AddCommand(shellCmd)
+	RootCmd.AddCommand(nodeShellCmd)
+	RootCmd.AddCommand(securityCmd)
+	RootCmd.AddCommand(restartsCmd)
+	RootCmd.AddCommand(tokensCmd)
+	RootCmd.AddCommand(exportCmd)
+	RootCmd.AddCommand(lintSelectorsCmd)
+	RootCmd.AddCommand(probeCmd)
+	RootCmd.AddCommand(nsinfoCmd)
+	RootCmd.AddCommand(onNodeCmd)
+	RootCmd.AddCommand(ipdumpCmd)
+	RootCmd.AddCommand(execCmd)
+}
+
+// finishCommand bir komut bittikten sonra yapılacakları yapar, shell de her satırdan sonra çağırır
+func finishCommand(cmd *cobra.Command, args []string, err error) {
+	// Hata mesajı pager kapandıktan sonra görünsün
+	stopPager()
+	// Yavaşlık client tarafındaki QPS limitinden geliyorsa söyleyelim
+	printThrottleHint()
+	// Hatalı çağrılar da history'e yazılsın
+	recordHistory(cmd, args, err)
+	if err != nil {
+		printError(err)
+	}
+}
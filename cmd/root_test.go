@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// TestNoFlagShadowsPersistentFlag fails when a subcommand defines a local flag with the
+// name or shorthand of a persistent root flag, e.g. --context or --debug: the local flag
+// wins and the global one silently stops working on that command.
+func TestNoFlagShadowsPersistentFlag(t *testing.T) {
+	addCommands()
+	persistent := RootCmd.PersistentFlags()
+
+	walkCommands(RootCmd, func(c *cobra.Command) {
+		if c == RootCmd {
+			return
+		}
+		c.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if persistent.Lookup(f.Name) != nil {
+				t.Errorf("%s: --%s shadows the global --%s", c.CommandPath(), f.Name, f.Name)
+			}
+			if f.Shorthand != "" {
+				if global := persistent.ShorthandLookup(f.Shorthand); global != nil {
+					t.Errorf("%s: -%s (--%s) shadows the global -%s (--%s)", c.CommandPath(), f.Shorthand, f.Name, f.Shorthand, global.Name)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// routeGVR is the OpenShift Route resource. Vanilla clusters don't serve it.
+var routeGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// RouteInfo is an OpenShift Route sending traffic to one of the listed services.
+type RouteInfo struct {
+	Name      string
+	Namespace string
+	Host      string
+	Path      string
+	Service   string
+	TLS       string
+	// ReadyEndpoints and TotalEndpoints count the endpoints of Service, i.e. the pods behind the host.
+	ReadyEndpoints int
+	TotalEndpoints int
+}
+
+// routesAvailable asks API discovery whether the cluster serves OpenShift Routes.
+func routesAvailable(clientset kubernetes.Interface) (bool, error) {
+	_, err := clientset.Discovery().ServerResourcesForGroupVersion(routeGVR.GroupVersion().String())
+	switch {
+	case err == nil:
+		return true, nil
+	case apierrors.IsNotFound(err):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to discover %s: %w", routeGVR.GroupVersion(), err)
+	}
+}
+
+// findServiceRoutes returns the Routes pointing at any of the services, as a primary or
+// alternate backend. It returns nothing on clusters without Routes.
+func findServiceRoutes(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, services []ServiceInfo) ([]RouteInfo, error) {
+	available, err := routesAvailable(clientset)
+	if err != nil || !available {
+		return nil, err
+	}
+	restConfig, err := configFlags.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	routes, err := listAcrossNamespaces(configFlags, func(ns string) ([]unstructured.Unstructured, error) {
+		list, err := client.Resource(routeGVR).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve routes: %w", err)
+	}
+
+	byService := map[string]ServiceInfo{}
+	for _, s := range services {
+		byService[s.Namespace+"/"+s.Name] = s
+	}
+	var infos []RouteInfo
+	for _, route := range routes {
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		path, _, _ := unstructured.NestedString(route.Object, "spec", "path")
+		tls, _, _ := unstructured.NestedString(route.Object, "spec", "tls", "termination")
+		if tls == "" {
+			tls = "-"
+		}
+
+		var backends []string
+		if name, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name"); name != "" {
+			backends = append(backends, name)
+		}
+		alternates, _, _ := unstructured.NestedSlice(route.Object, "spec", "alternateBackends")
+		for _, alt := range alternates {
+			if m, ok := alt.(map[string]interface{}); ok {
+				if name, ok := m["name"].(string); ok {
+					backends = append(backends, name)
+				}
+			}
+		}
+
+		for _, backend := range backends {
+			svc, ok := byService[route.GetNamespace()+"/"+backend]
+			if !ok {
+				continue
+			}
+			infos = append(infos, RouteInfo{
+				Name:           route.GetName(),
+				Namespace:      route.GetNamespace(),
+				Host:           host,
+				Path:           path,
+				Service:        backend,
+				TLS:            tls,
+				ReadyEndpoints: svc.ReadyEndpoints,
+				TotalEndpoints: svc.TotalEndpoints,
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Host != infos[j].Host {
+			return infos[i].Host < infos[j].Host
+		}
+		return infos[i].Path < infos[j].Path
+	})
+	return infos, nil
+}
+
+// printRouteTable prints host -> service -> endpoints for every route.
+func printRouteTable(routes []RouteInfo) {
+	t := newTable("HOST", "PATH", "ROUTE", "NAMESPACE", "SERVICE", "TLS", "ENDPOINTS")
+	for _, r := range routes {
+		path := r.Path
+		if path == "" {
+			path = "/"
+		}
+		t.addRow(r.Host, path, r.Name, r.Namespace, r.Service, r.TLS, fmt.Sprintf("%d/%d", r.ReadyEndpoints, r.TotalEndpoints))
+		if r.ReadyEndpoints == 0 {
+			t.colorCell(6, color.New(color.FgRed))
+		}
+	}
+	t.print(color.Output)
+}
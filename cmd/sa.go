@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SaInfo holds the ServiceAccount a pod runs as and what it is allowed to do.
+type SaInfo struct {
+	Pod            string
+	Namespace      string
+	ServiceAccount string
+	Automount      string
+	TokenExpiry    string
+	Roles          []string
+}
+
+// saCmd shows the ServiceAccount, RBAC bindings and token settings of the matching pods.
+var saCmd = &cobra.Command{
+	Use:   "sa [SEARCH_PATTERN]",
+	Short: "Show the ServiceAccount, bound roles and token automount/expiry of pods containing [SEARCH_PATTERN].",
+	RunE:  runSaFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(saCmd, "pods")
+}
+
+// runSaFunc returns a function that resolves every matching pod's ServiceAccount to the
+// roles and cluster roles bound to it.
+func runSaFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper sa api\nor:\n  kubectl helper sa -n dev api")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// ClusterRoleBindings apply everywhere; RoleBindings and ServiceAccounts are
+		// fetched once per namespace.
+		clusterBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+		clusterBindingsForbidden := apierrors.IsForbidden(err)
+		if err != nil && !clusterBindingsForbidden {
+			return fmt.Errorf("failed to retrieve clusterrolebindings: %w", err)
+		}
+		roleBindings := map[string][]rbacv1.RoleBinding{}
+		serviceAccounts := map[string]*corev1.ServiceAccount{}
+		secretTypes := map[string]corev1.SecretType{}
+
+		var rows []SaInfo
+		for i := range pods {
+			pod := &pods[i]
+			saName := pod.Spec.ServiceAccountName
+			if saName == "" {
+				saName = "default"
+			}
+
+			saKey := pod.Namespace + "/" + saName
+			sa, ok := serviceAccounts[saKey]
+			if !ok {
+				sa, err = clientset.CoreV1().ServiceAccounts(pod.Namespace).Get(ctx, saName, metav1.GetOptions{})
+				if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+					return fmt.Errorf("failed to retrieve serviceaccount %s: %w", saName, err)
+				}
+				if err != nil {
+					sa = nil
+				}
+				serviceAccounts[saKey] = sa
+			}
+
+			bindings, ok := roleBindings[pod.Namespace]
+			if !ok {
+				bindings, err = namespaceRoleBindings(ctx, clientset, pod.Namespace)
+				if err != nil {
+					return err
+				}
+				roleBindings[pod.Namespace] = bindings
+			}
+
+			var roles []string
+			for _, rb := range bindings {
+				if bindsServiceAccount(rb.Subjects, pod.Namespace, saName) {
+					roles = append(roles, fmt.Sprintf("%s/%s", rb.RoleRef.Kind, rb.RoleRef.Name))
+				}
+			}
+			if clusterBindingsForbidden {
+				roles = append(roles, "<clusterrolebindings forbidden>")
+			} else {
+				for _, crb := range clusterBindings.Items {
+					if bindsServiceAccount(crb.Subjects, pod.Namespace, saName) {
+						roles = append(roles, fmt.Sprintf("ClusterRole/%s (cluster-wide)", crb.RoleRef.Name))
+					}
+				}
+			}
+			sort.Strings(roles)
+			expiry, err := tokenExpiry(ctx, clientset, pod, secretTypes)
+			if err != nil {
+				return err
+			}
+
+			rows = append(rows, SaInfo{
+				Pod:            pod.Name,
+				Namespace:      pod.Namespace,
+				ServiceAccount: saName,
+				Automount:      automountSetting(pod, sa),
+				TokenExpiry:    expiry,
+				Roles:          roles,
+			})
+		}
+
+		printSaTable(rows)
+		return nil
+	}
+}
+
+// namespaceRoleBindings lists the RoleBindings of namespace. Without permission to read
+// them, nil is returned and the pods simply show no namespaced roles.
+func namespaceRoleBindings(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]rbacv1.RoleBinding, error) {
+	list, err := clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve rolebindings: %w", err)
+	}
+	return list.Items, nil
+}
+
+// bindsServiceAccount reports whether subjects grant to the ServiceAccount, either directly
+// or through the system:serviceaccounts groups.
+func bindsServiceAccount(subjects []rbacv1.Subject, namespace, name string) bool {
+	for _, s := range subjects {
+		switch s.Kind {
+		case rbacv1.ServiceAccountKind:
+			if s.Name == name && s.Namespace == namespace {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if s.Name == "system:serviceaccounts" || s.Name == "system:serviceaccounts:"+namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// automountSetting tells whether the API token is mounted into pod, and which object decides it.
+// The pod's setting wins over the ServiceAccount's, and both default to true.
+func automountSetting(pod *corev1.Pod, sa *corev1.ServiceAccount) string {
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		return fmt.Sprintf("%t (pod)", *pod.Spec.AutomountServiceAccountToken)
+	}
+	if sa != nil && sa.AutomountServiceAccountToken != nil {
+		return fmt.Sprintf("%t (sa)", *sa.AutomountServiceAccountToken)
+	}
+	return "true (default)"
+}
+
+// tokenExpiry returns the expiration of the ServiceAccount tokens mounted into pod, as the
+// tokens command shows them, or "-" when no token is mounted.
+func tokenExpiry(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, secretTypes map[string]corev1.SecretType) (string, error) {
+	infos, err := podTokenInfos(ctx, clientset, pod, secretTypes)
+	if err != nil {
+		return "", err
+	}
+	var expiries []string
+	for _, info := range infos {
+		expiry := tokenExpirationText(info)
+		if info.Legacy {
+			expiry += " (legacy secret)"
+		}
+		expiries = append(expiries, expiry)
+	}
+	if len(expiries) == 0 {
+		return "-", nil
+	}
+	return strings.Join(expiries, ","), nil
+}
+
+// printSaTable prints one row per pod, with broad permissions highlighted.
+func printSaTable(rows []SaInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	dangerColor := color.New(color.FgRed, color.Bold)
+
+	fmt.Println()
+	headerColor.Printf("%-35s %-15s %-25s %-16s %-15s %-50s\n",
+		"POD", "NAMESPACE", "SERVICE ACCOUNT", "AUTOMOUNT", "TOKEN EXPIRY", "ROLES")
+	line := strings.Repeat("-", 160)
+	lineColor.Println(line)
+
+	for _, r := range rows {
+		fmt.Printf("%-35s %-15s %-25s %-16s %-15s ", r.Pod, r.Namespace, r.ServiceAccount, r.Automount, r.TokenExpiry)
+		if len(r.Roles) == 0 {
+			fmt.Println("<none>")
+			continue
+		}
+		for i, role := range r.Roles {
+			if i > 0 {
+				fmt.Print(", ")
+			}
+			if strings.Contains(role, "cluster-admin") {
+				dangerColor.Print(role)
+			} else {
+				fmt.Print(role)
+			}
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ScaleTarget is a Deployment or StatefulSet resolved from the scale pattern.
+type ScaleTarget struct {
+	Kind      string
+	Name      string
+	Namespace string
+	Current   int32
+}
+
+// scale command flags.
+var (
+	scaleReplicas int32
+	scaleYes      bool
+	scaleWait     bool
+	scaleTimeout  time.Duration
+)
+
+// scaleCmd scales the Deployments and StatefulSets matching a pattern.
+var scaleCmd = &cobra.Command{
+	Use:   "scale [SEARCH_PATTERN]",
+	Short: "Scale the Deployments and StatefulSets owning pods containing [SEARCH_PATTERN], or named like it, to --replicas.",
+	RunE:  runScaleFunc(configFlags),
+	// The table has to be on screen before the confirmation prompt.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(scaleCmd, "workloads")
+	addDryRunFlag(scaleCmd)
+	scaleCmd.Flags().Int32Var(&scaleReplicas, "replicas", -1, "The new number of replicas. Required.")
+	scaleCmd.Flags().BoolVarP(&scaleYes, "yes", "y", false, "Scale without asking for confirmation.")
+	scaleCmd.Flags().BoolVar(&scaleWait, "wait", false, "Wait until the scaled workloads have the new number of ready replicas.")
+	scaleCmd.Flags().DurationVar(&scaleTimeout, "timeout", 5*time.Minute, "How long --wait waits.")
+}
+
+// runScaleFunc returns a function that resolves, confirms and scales the matching workloads.
+func runScaleFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper scale api --replicas 3\nor:\n  kubectl helper scale -n dev api --replicas 0 --dry-run")
+		}
+		searchTerm := args[0]
+		if scaleReplicas < 0 {
+			return fmt.Errorf("please provide the new number of replicas with --replicas")
+		}
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		targets, err := findScaleTargets(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		historyMatches = len(targets)
+		if len(targets) == 0 {
+			fmt.Printf("No deployments or statefulsets found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		printScaleTable(targets)
+		if dryRun == dryRunNone && !scaleYes {
+			ok, err := confirm(fmt.Sprintf("Scale %d workload(s) to %d replicas?", len(targets), scaleReplicas))
+			if err != nil || !ok {
+				return err
+			}
+		}
+
+		for _, t := range targets {
+			if err := applyScale(ctx, clientset, t, dryRun); err != nil {
+				return err
+			}
+		}
+		if !scaleWait || dryRun != dryRunNone {
+			return nil
+		}
+		for _, t := range targets {
+			if err := waitForScale(ctx, clientset, t); err != nil {
+				return err
+			}
+			color.New(color.FgGreen).Printf("%s/%s has %d ready replica(s)\n", t.Kind, t.Name, scaleReplicas)
+		}
+		return nil
+	}
+}
+
+// findScaleTargets returns the Deployments and StatefulSets whose name matches the
+// pattern or that own a pod whose name does.
+func findScaleTargets(ctx context.Context, configFlags *genericclioptions.ConfigFlags, clientset kubernetes.Interface, pattern string) ([]ScaleTarget, error) {
+	deployments, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.Deployment, error) {
+		list, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve deployments: %w", err)
+	}
+	statefulSets, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.StatefulSet, error) {
+		list, err := clientset.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve statefulsets: %w", err)
+	}
+	pods, err := findPods(ctx, configFlags, clientset, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := newOwnerResolver(configFlags)
+	if err != nil {
+		return nil, err
+	}
+	owners := map[string]bool{}
+	for i := range pods {
+		kind, name := resolver.podWorkload(ctx, &pods[i])
+		owners[kind+"/"+pods[i].Namespace+"/"+name] = true
+	}
+	var targets []ScaleTarget
+	for _, d := range deployments {
+		if matchesPattern(d.Name, pattern) || owners["Deployment/"+d.Namespace+"/"+d.Name] {
+			targets = append(targets, ScaleTarget{Kind: "Deployment", Name: d.Name, Namespace: d.Namespace, Current: replicasOf(d.Spec.Replicas)})
+		}
+	}
+	for _, s := range statefulSets {
+		if matchesPattern(s.Name, pattern) || owners["StatefulSet/"+s.Namespace+"/"+s.Name] {
+			targets = append(targets, ScaleTarget{Kind: "StatefulSet", Name: s.Name, Namespace: s.Namespace, Current: replicasOf(s.Spec.Replicas)})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Namespace != targets[j].Namespace {
+			return targets[i].Namespace < targets[j].Namespace
+		}
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, nil
+}
+
+// replicasOf returns the replicas of a spec, which default to 1 when unset.
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// applyScale sets the replicas of t through its scale subresource.
+func applyScale(ctx context.Context, clientset kubernetes.Interface, t ScaleTarget, dryRun dryRunStrategy) error {
+	kind := "deployment.apps"
+	if t.Kind == "StatefulSet" {
+		kind = "statefulset.apps"
+	}
+	if dryRun == dryRunClient {
+		printChange(dryRun, "%s/%s scaled", kind, t.Name)
+		return nil
+	}
+
+	scale := &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{Name: t.Name, Namespace: t.Namespace},
+		Spec:       autoscalingv1.ScaleSpec{Replicas: scaleReplicas},
+	}
+	opts := metav1.UpdateOptions{DryRun: dryRun.serverDryRun()}
+	var err error
+	if t.Kind == "StatefulSet" {
+		_, err = clientset.AppsV1().StatefulSets(t.Namespace).UpdateScale(ctx, t.Name, scale, opts)
+	} else {
+		_, err = clientset.AppsV1().Deployments(t.Namespace).UpdateScale(ctx, t.Name, scale, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scale %s/%s: %w", kind, t.Name, err)
+	}
+	printChange(dryRun, "%s/%s scaled", kind, t.Name)
+	return nil
+}
+
+// waitForScale polls t until its rollout has the new number of ready replicas.
+func waitForScale(ctx context.Context, clientset kubernetes.Interface, t ScaleTarget) error {
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, scaleTimeout, true, func(ctx context.Context) (bool, error) {
+		if t.Kind == "StatefulSet" {
+			s, err := clientset.AppsV1().StatefulSets(t.Namespace).Get(ctx, t.Name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			return s.Status.ObservedGeneration >= s.Generation && s.Status.Replicas == scaleReplicas &&
+				s.Status.ReadyReplicas == scaleReplicas, nil
+		}
+		d, err := clientset.AppsV1().Deployments(t.Namespace).Get(ctx, t.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return d.Status.ObservedGeneration >= d.Generation && d.Status.Replicas == scaleReplicas &&
+			d.Status.ReadyReplicas == scaleReplicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s/%s did not reach %d ready replica(s): %w", t.Kind, t.Name, scaleReplicas, err)
+	}
+	return nil
+}
+
+// printScaleTable shows the current and the target replicas of every workload.
+func printScaleTable(targets []ScaleTarget) {
+	t := newTable("KIND", "NAME", "NAMESPACE", "CURRENT", "TARGET")
+	changeColor := color.New(color.FgYellow)
+	for _, target := range targets {
+		t.addRow(target.Kind, target.Name, target.Namespace, strconv.Itoa(int(target.Current)), strconv.Itoa(int(scaleReplicas)))
+		if target.Current != scaleReplicas {
+			t.colorCell(4, changeColor)
+		}
+	}
+	t.print(color.Output)
+}
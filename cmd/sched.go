@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// schedulingHints maps fragments of the scheduler's FailedScheduling message to the
+// pod setting that causes them.
+var schedulingHints = []struct {
+	fragment string
+	hint     string
+}{
+	{"node affinity/selector", "nodeSelector / node affinity"},
+	{"untolerated taint", "tolerations"},
+	{"had taint", "tolerations"},
+	{"anti-affinity", "pod anti-affinity"},
+	{"pod affinity", "pod affinity"},
+	{"topology spread constraints", "topologySpreadConstraints"},
+	{"Insufficient", "resource requests"},
+	{"Too many pods", "node pod capacity"},
+	{"free ports", "hostPort"},
+	{"volume node affinity conflict", "PersistentVolume node affinity"},
+	{"PersistentVolumeClaims", "volumes"},
+	{"were unschedulable", "cordoned nodes"},
+}
+
+// schedCmd summarizes the scheduling constraints of the matching pods.
+var schedCmd = &cobra.Command{
+	Use:   "sched [SEARCH_PATTERN]",
+	Short: "Show the nodeSelector, affinity, tolerations and spread constraints of pods containing [SEARCH_PATTERN].",
+	RunE:  runSchedFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(schedCmd, "pods")
+}
+
+// runSchedFunc returns a function that prints the scheduling constraints of every matching
+// pod and, for Pending pods, which of them the scheduler reports as failing.
+func runSchedFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper sched api\nor:\n  kubectl helper sched -n dev api")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		for i := range pods {
+			pod := &pods[i]
+			var failures []string
+			if pod.Status.Phase == corev1.PodPending && pod.Spec.NodeName == "" {
+				events, err := podEvents(ctx, clientset, pod)
+				if err != nil {
+					return err
+				}
+				for _, ev := range events {
+					if ev.Reason == "FailedScheduling" {
+						failures = explainFailedScheduling(ev.Message)
+						break
+					}
+				}
+			}
+			printSchedReport(pod, failures)
+		}
+		return nil
+	}
+}
+
+// explainFailedScheduling splits a message like "0/5 nodes are available: 3 node(s) didn't
+// match Pod's node affinity/selector, 2 node(s) had untolerated taint {...}. preemption: ..."
+// into its reasons, each followed by the pod setting responsible for it.
+func explainFailedScheduling(message string) []string {
+	_, reasons, found := strings.Cut(message, "are available: ")
+	if !found {
+		return []string{message}
+	}
+	// The preemption verdict repeats the reasons and adds nothing.
+	reasons, _, _ = strings.Cut(reasons, " preemption:")
+	reasons = strings.TrimSuffix(strings.TrimSpace(reasons), ".")
+
+	var explained []string
+	for _, reason := range strings.Split(reasons, ", ") {
+		reason = strings.TrimSpace(reason)
+		for _, h := range schedulingHints {
+			if strings.Contains(reason, h.fragment) {
+				reason = fmt.Sprintf("%s -> check %s", reason, h.hint)
+				break
+			}
+		}
+		explained = append(explained, reason)
+	}
+	return explained
+}
+
+// formatLabelSelector renders a label selector like "app=web, tier In (a,b)".
+func formatLabelSelector(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return "<none>"
+	}
+	s := metav1.FormatLabelSelector(selector)
+	if s == "<none>" || s == "" {
+		return "<all pods>"
+	}
+	return s
+}
+
+// formatNodeSelectorTerm renders the expressions of one node selector term joined by "AND".
+func formatNodeSelectorTerm(term corev1.NodeSelectorTerm) string {
+	var parts []string
+	for _, reqs := range [][]corev1.NodeSelectorRequirement{term.MatchExpressions, term.MatchFields} {
+		for _, r := range reqs {
+			switch r.Operator {
+			case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+				parts = append(parts, fmt.Sprintf("%s %s", r.Key, r.Operator))
+			default:
+				parts = append(parts, fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ",")))
+			}
+		}
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// nodeAffinityLines renders the required and preferred node affinity terms.
+func nodeAffinityLines(affinity *corev1.NodeAffinity) []string {
+	if affinity == nil {
+		return nil
+	}
+	var lines []string
+	if req := affinity.RequiredDuringSchedulingIgnoredDuringExecution; req != nil {
+		// Terms are ORed by the scheduler.
+		for _, term := range req.NodeSelectorTerms {
+			lines = append(lines, "required: "+formatNodeSelectorTerm(term))
+		}
+	}
+	for _, pref := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		lines = append(lines, fmt.Sprintf("preferred (weight %d): %s", pref.Weight, formatNodeSelectorTerm(pref.Preference)))
+	}
+	return lines
+}
+
+// podAffinityLines renders the required and preferred terms of a pod (anti-)affinity.
+func podAffinityLines(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm) []string {
+	format := func(term corev1.PodAffinityTerm) string {
+		s := fmt.Sprintf("%s per %s", formatLabelSelector(term.LabelSelector), term.TopologyKey)
+		if len(term.Namespaces) > 0 {
+			s += fmt.Sprintf(" in %s", strings.Join(term.Namespaces, ","))
+		}
+		return s
+	}
+	var lines []string
+	for _, term := range required {
+		lines = append(lines, "required: "+format(term))
+	}
+	for _, pref := range preferred {
+		lines = append(lines, fmt.Sprintf("preferred (weight %d): %s", pref.Weight, format(pref.PodAffinityTerm)))
+	}
+	return lines
+}
+
+// formatToleration renders a toleration like kubectl describe does, e.g. "node.kubernetes.io/not-ready:NoExecute for 300s".
+func formatToleration(t corev1.Toleration) string {
+	s := t.Key
+	if t.Key == "" && t.Operator == corev1.TolerationOpExists {
+		s = "<all taints>"
+	}
+	if t.Value != "" {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + string(t.Effect)
+	}
+	if t.TolerationSeconds != nil {
+		s += fmt.Sprintf(" for %ds", *t.TolerationSeconds)
+	}
+	return s
+}
+
+// printSchedReport prints the scheduling constraints of one pod, followed by the reasons
+// the scheduler gave for not placing it.
+func printSchedReport(pod *corev1.Pod, failures []string) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	labelColor := color.New(color.FgCyan)
+	errorColor := color.New(color.FgRed)
+
+	node := pod.Spec.NodeName
+	if node == "" {
+		node = "<not scheduled>"
+	}
+
+	fmt.Println()
+	headerColor.Printf("%s/%s (%s on %s)\n", pod.Namespace, pod.Name, pod.Status.Phase, node)
+	line := strings.Repeat("-", 100)
+	lineColor.Println(line)
+
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			lines = []string{"-"}
+		}
+		for i, l := range lines {
+			if i == 0 {
+				labelColor.Printf("%-20s ", title)
+			} else {
+				fmt.Printf("%-20s ", "")
+			}
+			fmt.Println(l)
+		}
+	}
+
+	var selector []string
+	for k, v := range pod.Spec.NodeSelector {
+		selector = append(selector, k+"="+v)
+	}
+	sort.Strings(selector)
+	section("NODE SELECTOR", selector)
+
+	var nodeAffinity, podAffinity, podAntiAffinity []string
+	if a := pod.Spec.Affinity; a != nil {
+		nodeAffinity = nodeAffinityLines(a.NodeAffinity)
+		if a.PodAffinity != nil {
+			podAffinity = podAffinityLines(a.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				a.PodAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+		}
+		if a.PodAntiAffinity != nil {
+			podAntiAffinity = podAffinityLines(a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				a.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+		}
+	}
+	section("NODE AFFINITY", nodeAffinity)
+	section("POD AFFINITY", podAffinity)
+	section("POD ANTI-AFFINITY", podAntiAffinity)
+
+	var tolerations []string
+	for _, t := range pod.Spec.Tolerations {
+		tolerations = append(tolerations, formatToleration(t))
+	}
+	section("TOLERATIONS", tolerations)
+
+	var spread []string
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		spread = append(spread, fmt.Sprintf("maxSkew %d per %s (%s) for %s",
+			c.MaxSkew, c.TopologyKey, c.WhenUnsatisfiable, formatLabelSelector(c.LabelSelector)))
+	}
+	section("TOPOLOGY SPREAD", spread)
+
+	for _, f := range failures {
+		errorColor.Printf("! %s\n", f)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// SecurityInfo holds the effective security settings of one container of a matched pod.
+// Pod-level settings apply where the container doesn't set its own.
+type SecurityInfo struct {
+	Pod       string
+	Namespace string
+	Container string
+	// Kind is "init" or "ephemeral" for those containers, empty for regular ones.
+	Kind string
+	// RunAsUser is nil when neither the pod nor the container set it and the image decides.
+	RunAsUser    *int64
+	RunAsNonRoot bool
+	Privileged   bool
+	// AllowPrivilegeEscalation is nil when unset, which allows it.
+	AllowPrivilegeEscalation *bool
+	HostPID                  bool
+	HostIPC                  bool
+	HostNetwork              bool
+	// Capabilities are the added capabilities.
+	Capabilities []string
+	// Seccomp is the profile type, with the profile for Localhost; empty when unset.
+	Seccomp string
+}
+
+// security command filters.
+var (
+	securityPrivilegedOnly bool
+	securityRootOnly       bool
+	securityHostOnly       bool
+)
+
+// securityCmd audits the security contexts of the matching pods.
+var securityCmd = &cobra.Command{
+	Use:   "security [SEARCH_PATTERN]",
+	Short: "Show runAsUser, privileged mode, host namespaces, added capabilities and seccomp profile of the containers of pods containing [SEARCH_PATTERN].",
+	RunE:  runSecurityFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(securityCmd, "pods")
+	securityCmd.Flags().BoolVar(&securityPrivilegedOnly, "privileged-only", false,
+		"Only show privileged containers.")
+	securityCmd.Flags().BoolVar(&securityRootOnly, "root-only", false,
+		"Only show containers that may run as root: runAsUser 0, or neither runAsUser nor runAsNonRoot set.")
+	securityCmd.Flags().BoolVar(&securityHostOnly, "host-only", false,
+		"Only show containers of pods sharing the host's PID, IPC or network namespace.")
+}
+
+// runSecurityFunc returns a function that prints one row per container of the matching pods.
+func runSecurityFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper security nginx\nor:\n  kubectl helper security -A --privileged-only \"\"")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+
+		var rows []SecurityInfo
+		for i := range pods {
+			for _, info := range podSecurityInfos(&pods[i]) {
+				if securityPrivilegedOnly && !info.Privileged {
+					continue
+				}
+				if securityRootOnly && !info.mayRunAsRoot() {
+					continue
+				}
+				if securityHostOnly && !info.HostPID && !info.HostIPC && !info.HostNetwork {
+					continue
+				}
+				rows = append(rows, info)
+			}
+		}
+		historyMatches = len(rows)
+		if len(rows) == 0 {
+			fmt.Printf("No containers found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].Namespace != rows[j].Namespace {
+				return rows[i].Namespace < rows[j].Namespace
+			}
+			return rows[i].Pod < rows[j].Pod
+		})
+		printSecurityTable(rows)
+		return nil
+	}
+}
+
+// podSecurityInfos returns the effective settings of the init, regular and ephemeral
+// containers of pod.
+func podSecurityInfos(pod *corev1.Pod) []SecurityInfo {
+	podSC := pod.Spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+
+	var infos []SecurityInfo
+	add := func(name, kind string, sc *corev1.SecurityContext) {
+		info := SecurityInfo{
+			Pod:          pod.Name,
+			Namespace:    pod.Namespace,
+			Container:    name,
+			Kind:         kind,
+			RunAsUser:    podSC.RunAsUser,
+			RunAsNonRoot: podSC.RunAsNonRoot != nil && *podSC.RunAsNonRoot,
+			HostPID:      pod.Spec.HostPID,
+			HostIPC:      pod.Spec.HostIPC,
+			HostNetwork:  pod.Spec.HostNetwork,
+			Seccomp:      seccompProfile(podSC.SeccompProfile),
+		}
+		if sc != nil {
+			if sc.RunAsUser != nil {
+				info.RunAsUser = sc.RunAsUser
+			}
+			if sc.RunAsNonRoot != nil {
+				info.RunAsNonRoot = *sc.RunAsNonRoot
+			}
+			info.Privileged = sc.Privileged != nil && *sc.Privileged
+			info.AllowPrivilegeEscalation = sc.AllowPrivilegeEscalation
+			if sc.Capabilities != nil {
+				for _, c := range sc.Capabilities.Add {
+					info.Capabilities = append(info.Capabilities, string(c))
+				}
+			}
+			if sc.SeccompProfile != nil {
+				info.Seccomp = seccompProfile(sc.SeccompProfile)
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	for _, c := range pod.Spec.InitContainers {
+		add(c.Name, "init", c.SecurityContext)
+	}
+	for _, c := range pod.Spec.Containers {
+		add(c.Name, "", c.SecurityContext)
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		add(c.Name, "ephemeral", c.SecurityContext)
+	}
+	return infos
+}
+
+// seccompProfile formats a seccomp profile, e.g. "RuntimeDefault" or "Localhost:audit.json".
+func seccompProfile(p *corev1.SeccompProfile) string {
+	if p == nil {
+		return ""
+	}
+	if p.Type == corev1.SeccompProfileTypeLocalhost && p.LocalhostProfile != nil {
+		return string(p.Type) + ":" + *p.LocalhostProfile
+	}
+	return string(p.Type)
+}
+
+// mayRunAsRoot reports whether the container runs as root, or may do so depending on its image.
+func (s SecurityInfo) mayRunAsRoot() bool {
+	if s.RunAsUser != nil {
+		return *s.RunAsUser == 0
+	}
+	return !s.RunAsNonRoot
+}
+
+// printSecurityTable prints a row per container: risky settings red, weak defaults yellow.
+func printSecurityTable(rows []SecurityInfo) {
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("POD", "NAMESPACE", "CONTAINER", "USER", "PRIVILEGED", "ESCALATION", "HOST", "CAPABILITIES", "SECCOMP")
+	for _, s := range rows {
+		container := s.Container
+		if s.Kind != "" {
+			container += " (" + s.Kind + ")"
+		}
+		user := "image"
+		switch {
+		case s.RunAsUser != nil:
+			user = fmt.Sprintf("%d", *s.RunAsUser)
+		case s.RunAsNonRoot:
+			user = "non-root"
+		}
+		escalation := "allowed"
+		if s.AllowPrivilegeEscalation != nil && !*s.AllowPrivilegeEscalation {
+			escalation = "denied"
+		}
+		var host []string
+		if s.HostPID {
+			host = append(host, "pid")
+		}
+		if s.HostIPC {
+			host = append(host, "ipc")
+		}
+		if s.HostNetwork {
+			host = append(host, "net")
+		}
+		hostNS, caps, seccomp := "-", "-", s.Seccomp
+		if len(host) > 0 {
+			hostNS = strings.Join(host, ",")
+		}
+		if len(s.Capabilities) > 0 {
+			caps = strings.Join(s.Capabilities, ",")
+		}
+		if seccomp == "" {
+			seccomp = "-"
+		}
+		t.addRow(s.Pod, s.Namespace, container, user, fmt.Sprintf("%t", s.Privileged), escalation, hostNS, caps, seccomp)
+
+		switch {
+		case s.RunAsUser != nil && *s.RunAsUser == 0:
+			t.colorCell(3, errorColor)
+		case s.mayRunAsRoot():
+			t.colorCell(3, warnColor)
+		}
+		if s.Privileged {
+			t.colorCell(4, errorColor)
+		}
+		if escalation == "allowed" {
+			t.colorCell(5, warnColor)
+		}
+		if len(host) > 0 {
+			t.colorCell(6, errorColor)
+		}
+		if len(s.Capabilities) > 0 {
+			t.colorCell(7, warnColor)
+		}
+		if s.Seccomp == "" || s.Seccomp == string(corev1.SeccompProfileTypeUnconfined) {
+			t.colorCell(8, warnColor)
+		}
+	}
+	t.print(color.Output)
+}
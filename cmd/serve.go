@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// serve command flags.
+var (
+	serveListen   string
+	servePatterns []string
+)
+
+// PatternSummary is the state of the pods matching one served pattern.
+type PatternSummary struct {
+	Pattern   string `json:"pattern"`
+	Namespace string `json:"namespace"`
+	Pods      int    `json:"pods"`
+	NotReady  int    `json:"notReady"`
+	Restarts  int32  `json:"restarts"`
+}
+
+// serveCmd keeps the pods in an informer cache and serves metrics about them.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Prometheus metrics and a JSON API about the pods matching the configured patterns.",
+	RunE:  runServeFunc(configFlags),
+	// Serves until interrupted; its few lines of output need no pager.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(serveCmd, "pods")
+	serveCmd.Flags().StringVar(&serveListen, "listen", ":9090", "Address to serve /metrics and /api on.")
+	serveCmd.Flags().StringSliceVar(&servePatterns, "pattern", nil,
+		"Pod name patterns to report on, repeat the flag or separate with commas. Defaults to servePatterns from the config file.")
+}
+
+// runServeFunc returns a function that syncs the pod informers and serves until interrupted.
+func runServeFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		patterns := servePatterns
+		if len(patterns) == 0 {
+			patterns = helperConfig.ServePatterns
+		}
+		if len(patterns) == 0 {
+			return fmt.Errorf("please provide the patterns to report on, for example:\n  kubectl helper serve --pattern api,worker\nor set servePatterns in the config file")
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		namespaces, err := targetNamespaces(configFlags)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		// One informer per namespace; a single one covers the cluster for -A.
+		var listers []corelisters.PodLister
+		var synced []cache.InformerSynced
+		for _, ns := range namespaces {
+			factory := informers.NewSharedInformerFactoryWithOptions(clientset, 10*time.Minute, informers.WithNamespace(ns))
+			podInformer := factory.Core().V1().Pods()
+			listers = append(listers, podInformer.Lister())
+			synced = append(synced, podInformer.Informer().HasSynced)
+			factory.Start(ctx.Done())
+		}
+		if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+			return fmt.Errorf("failed to sync the pod cache")
+		}
+
+		listPods := func() ([]*corev1.Pod, error) {
+			var pods []*corev1.Pod
+			for _, l := range listers {
+				list, err := l.List(labels.Everything())
+				if err != nil {
+					return nil, err
+				}
+				pods = append(pods, list...)
+			}
+			return pods, nil
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+			pods, err := listPods()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			writeMetrics(w, summarizePatterns(pods, patterns, namespaces))
+		})
+		mux.HandleFunc("/api/summary", func(w http.ResponseWriter, r *http.Request) {
+			pods, err := listPods()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, summarizePatterns(pods, patterns, namespaces))
+		})
+		mux.HandleFunc("/api/pods", func(w http.ResponseWriter, r *http.Request) {
+			pods, err := listPods()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			// A resolver per request: its cache isn't safe for concurrent requests and
+			// would go stale over the life of the server.
+			resolver, err := newOwnerResolver(configFlags)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			pattern := r.URL.Query().Get("pattern")
+			var result []PodInfo
+			for _, pod := range pods {
+				if matchesPattern(pod.Name, pattern) {
+					result = append(result, podInfoFromPod(r.Context(), resolver, pod))
+				}
+			}
+			sort.Slice(result, func(i, j int) bool {
+				if result[i].Namespace != result[j].Namespace {
+					return result[i].Namespace < result[j].Namespace
+				}
+				return result[i].Name < result[j].Name
+			})
+			writeJSON(w, result)
+		})
+		mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, "ok")
+		})
+
+		server := &http.Server{Addr: serveListen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			server.Close()
+		}()
+
+		color.New(color.FgGreen).Fprintf(os.Stderr, "Serving /metrics, /api/summary and /api/pods?pattern= on %s for patterns: %s\n",
+			serveListen, strings.Join(patterns, ", "))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("failed to serve on %s: %w", serveListen, err)
+		}
+		return nil
+	}
+}
+
+// summarizePatterns counts the matched, not ready and restarted pods per pattern and namespace.
+// Every served namespace gets a zero summary for the patterns matching nothing in it, so
+// alerts on kubectl_helper_matched_pods == 0 can fire; with all namespaces that is a single
+// summary with an empty namespace.
+func summarizePatterns(pods []*corev1.Pod, patterns, namespaces []string) []PatternSummary {
+	byKey := map[[2]string]*PatternSummary{}
+	var summaries []*PatternSummary
+	for _, pattern := range patterns {
+		for _, pod := range pods {
+			if !matchesPattern(pod.Name, pattern) {
+				continue
+			}
+			key := [2]string{pattern, pod.Namespace}
+			s, ok := byKey[key]
+			if !ok {
+				s = &PatternSummary{Pattern: pattern, Namespace: pod.Namespace}
+				byKey[key] = s
+				summaries = append(summaries, s)
+			}
+			s.Pods++
+			if pod.Status.Phase != corev1.PodSucceeded && !isPodReady(pod) {
+				s.NotReady++
+			}
+			for _, st := range pod.Status.ContainerStatuses {
+				s.Restarts += st.RestartCount
+			}
+		}
+	}
+
+	for _, pattern := range patterns {
+		for _, ns := range namespaces {
+			if ns == metav1.NamespaceAll {
+				matched := false
+				for key := range byKey {
+					matched = matched || key[0] == pattern
+				}
+				if matched {
+					continue
+				}
+			} else if _, ok := byKey[[2]string{pattern, ns}]; ok {
+				continue
+			}
+			s := &PatternSummary{Pattern: pattern, Namespace: ns}
+			byKey[[2]string{pattern, ns}] = s
+			summaries = append(summaries, s)
+		}
+	}
+
+	result := make([]PatternSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Pattern != result[j].Pattern {
+			return result[i].Pattern < result[j].Pattern
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
+
+// writeMetrics writes the summaries in the Prometheus text exposition format.
+func writeMetrics(w http.ResponseWriter, summaries []PatternSummary) {
+	metrics := []struct {
+		name, help, kind string
+		value            func(PatternSummary) float64
+	}{
+		{"kubectl_helper_matched_pods", "Number of pods matching the pattern.", "gauge",
+			func(s PatternSummary) float64 { return float64(s.Pods) }},
+		{"kubectl_helper_not_ready_pods", "Number of matching pods that are not ready.", "gauge",
+			func(s PatternSummary) float64 { return float64(s.NotReady) }},
+		{"kubectl_helper_container_restarts", "Sum of the container restarts of the matching pods.", "gauge",
+			func(s PatternSummary) float64 { return float64(s.Restarts) }},
+	}
+	for _, m := range metrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%s{pattern=%q,namespace=%q} %g\n", m.name, s.Pattern, s.Namespace, m.value(s))
+		}
+	}
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// podInfoFromPod builds the PodInfo of a typed pod, as the ip command shows it, with the
+// top-level owner found by resolver.
+func podInfoFromPod(ctx context.Context, resolver *ownerResolver, pod *corev1.Pod) PodInfo {
+	owner, managedBy := "", ""
+	if ref := metav1.GetControllerOf(pod); ref != nil {
+		owner, managedBy = resolver.podOwner(ctx, pod.Namespace, *ref, pod.Labels)
+	}
+	return PodInfo{
+		Name:          pod.Name,
+		Namespace:     pod.Namespace,
+		IP:            pod.Status.PodIP,
+		NodeName:      pod.Spec.NodeName,
+		NodeIP:        pod.Status.HostIP,
+		Labels:        pod.Labels,
+		Annotations:   pod.Annotations,
+		Owner:         owner,
+		ManagedBy:     managedBy,
+		Init:          initColumn(pod),
+		Mesh:          podMesh(pod),
+		PriorityClass: pod.Spec.PriorityClassName,
+		Priority:      podPriority(pod),
+	}
+}
@@ -0,0 +1,474 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// shellCmd reads helper commands line by line and runs them in this process, so the
+// clients, the kubeconfig and the pod, service and node cache are set up only once.
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Open a prompt running helper commands (ip, logs, exec, svc, ...) against one session, with tab completion and a current namespace.",
+	Long: `Open a prompt running helper commands against one session. Type them without
+"kubectl helper", e.g. "ip nginx", "logs api --since 5m" or "exec api -- env".
+
+Commands without -n or -A run in the current namespace; "ns NAME" switches it, "ns -A"
+switches to all namespaces and "ns" alone shows it. Tab completes commands, flags,
+namespaces and pod, service or node names. "exit", "quit" or Ctrl+D leave the shell.
+
+Pods, services and nodes are cached for the whole session, the way kubectl helper daemon
+does, unless a daemon already runs for the context or --no-daemon is set. Connection
+flags such as --context belong on the shell itself and apply to every command.`,
+	RunE: runShellFunc(configFlags),
+	// The prompt needs the terminal; each command starts its own pager.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+// shellBuiltins are the commands the shell handles itself.
+var shellBuiltins = []string{"ns", "exit", "quit"}
+
+// shellCompletionTimeout bounds the API requests made to complete a name.
+const shellCompletionTimeout = 2 * time.Second
+
+// shellSession is the state kept between the lines of a shell.
+type shellSession struct {
+	ctx         context.Context
+	clientset   kubernetes.Interface
+	contextName string
+	// namespace is the current namespace, metav1.NamespaceAll for all of them.
+	namespace string
+	// flags are the values every flag gets back before a line runs.
+	flags map[*pflag.Flag]flagSnapshot
+	// baseArgs are the flags the shell was started with, recorded in the history.
+	baseArgs []string
+
+	terminal   *term.Terminal
+	scanner    *bufio.Scanner
+	namespaces []string
+}
+
+// flagSnapshot is the value of a flag at the start of the shell.
+type flagSnapshot struct {
+	value   string
+	slice   []string
+	changed bool
+}
+
+// runShellFunc returns a function that runs the prompt until exit or end of input.
+func runShellFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		raw, err := configFlags.ToRawKubeConfigLoader().RawConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load kubeconfig: %w", err)
+		}
+		s := &shellSession{ctx: cmd.Context(), clientset: clientset, contextName: raw.CurrentContext, flags: snapshotFlags()}
+		if configFlags.Context != nil && *configFlags.Context != "" {
+			s.contextName = *configFlags.Context
+		}
+		if !helperConfig.AllNamespacesByDefault {
+			s.namespace, _, err = configFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return fmt.Errorf("failed to determine namespace from kubeconfig: %w", err)
+			}
+		}
+		RootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Changed {
+				return
+			}
+			value := f.Value.String()
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				value = strings.Join(sv.GetSlice(), ",")
+			}
+			s.baseArgs = append(s.baseArgs, "--"+f.Name+"="+value)
+		})
+
+		ctx, stop := context.WithCancel(s.ctx)
+		cacheDone := s.startCache(ctx)
+		defer func() {
+			stop()
+			<-cacheDone
+		}()
+
+		if term.IsTerminal(int(os.Stdin.Fd())) && stdoutIsTerminal() {
+			s.terminal = term.NewTerminal(struct {
+				io.Reader
+				io.Writer
+			}{os.Stdin, terminalStdout}, "")
+			s.terminal.AutoCompleteCallback = s.complete
+		} else {
+			s.scanner = bufio.NewScanner(os.Stdin)
+		}
+
+		for {
+			line, err := s.readLine()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if s.runLine(line) {
+				return nil
+			}
+		}
+	}
+}
+
+// startCache serves the pod, service and node cache from this process while ctx lives,
+// the way the daemon does. The returned channel is closed once the socket is gone.
+func (s *shellSession) startCache(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	path, err := daemonSocketPath(configFlags)
+	if noDaemonFlag || err != nil || connectionOverridden(configFlags) || daemonRunning(path) {
+		close(done)
+		return done
+	}
+	if err := checkCacheAccess(ctx, s.clientset); err != nil {
+		color.New(color.FgYellow).Fprintf(os.Stderr, "Not caching pods, services and nodes: %v\n", err)
+		close(done)
+		return done
+	}
+
+	// The prompt is usable right away; commands query the API server until the cache synced.
+	go func() {
+		defer close(done)
+		server, err := startCacheServer(ctx, configFlags)
+		if err != nil {
+			if ctx.Err() == nil {
+				color.New(color.FgYellow).Fprintf(os.Stderr, "Not caching pods, services and nodes: %v\n", err)
+			}
+			return
+		}
+		server.serve()
+	}()
+	return done
+}
+
+// prompt shows the context and the current namespace.
+func (s *shellSession) prompt() string {
+	namespace := s.namespace
+	if namespace == metav1.NamespaceAll {
+		namespace = "*"
+	}
+	return color.New(color.FgCyan).Sprint(s.contextName) + "/" + color.New(color.FgGreen).Sprint(namespace) + "> "
+}
+
+// readLine reads the next line, from the terminal in raw mode for line editing and
+// completion, or from piped input. Ctrl+C, and Ctrl+D on an empty line, return io.EOF.
+func (s *shellSession) readLine() (string, error) {
+	if s.terminal == nil {
+		if !s.scanner.Scan() {
+			if err := s.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return s.scanner.Text(), nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+	if width, height, err := term.GetSize(fd); err == nil {
+		s.terminal.SetSize(width, height)
+	}
+	s.terminal.SetPrompt(s.prompt())
+	return s.terminal.ReadLine()
+}
+
+// runLine runs one line of input and reports whether the shell should exit.
+func (s *shellSession) runLine(line string) bool {
+	args, err := shlex.Split(line)
+	if err != nil {
+		printError(fmt.Errorf("failed to parse the line: %w", err))
+		return false
+	}
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "ns":
+		if len(args) > 1 {
+			s.namespace = args[1]
+			if args[1] == "-A" || args[1] == "--all-namespaces" {
+				s.namespace = metav1.NamespaceAll
+			}
+		}
+		if s.namespace == metav1.NamespaceAll {
+			fmt.Fprintln(terminalStdout, "all namespaces")
+		} else {
+			fmt.Fprintln(terminalStdout, s.namespace)
+		}
+		return false
+	case "shell":
+		printError(fmt.Errorf("already in the shell"))
+		return false
+	}
+
+	if target, _, err := RootCmd.Find(args); err == nil && target.Flags().Lookup("namespace") != nil && !hasNamespaceArg(args) {
+		args = withNamespace(args, s.namespace)
+	}
+
+	// Every line starts from the flags the shell started with, not those of the line before.
+	restoreFlags(s.flags)
+	historyMatches = -1
+	ctx, stop := signal.NotifyContext(s.ctx, os.Interrupt)
+	defer stop()
+	walkCommands(RootCmd, func(c *cobra.Command) {
+		c.SetContext(ctx)
+	})
+	RootCmd.SetArgs(args)
+	cmd, err := RootCmd.ExecuteC()
+	finishCommand(cmd, append(append([]string{}, s.baseArgs...), args...), err)
+	return false
+}
+
+// hasNamespaceArg reports whether args pick namespaces themselves.
+func hasNamespaceArg(args []string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "-A", arg == "--all-namespaces", strings.HasPrefix(arg, "--all-namespaces="),
+			strings.HasPrefix(arg, "-n"), arg == "--namespace", strings.HasPrefix(arg, "--namespace="):
+			return true
+		}
+	}
+	return false
+}
+
+// withNamespace adds the namespace flags for namespace to args, before any "--".
+func withNamespace(args []string, namespace string) []string {
+	flag := "--namespace=" + namespace
+	if namespace == metav1.NamespaceAll {
+		flag = "--all-namespaces"
+	}
+	out := make([]string, 0, len(args)+1)
+	for i, arg := range args {
+		if arg == "--" {
+			out = append(out, flag)
+			return append(out, args[i:]...)
+		}
+		out = append(out, arg)
+	}
+	return append(out, flag)
+}
+
+// walkCommands calls fn for c and all of its subcommands.
+func walkCommands(c *cobra.Command, fn func(*cobra.Command)) {
+	fn(c)
+	for _, sub := range c.Commands() {
+		walkCommands(sub, fn)
+	}
+}
+
+// snapshotFlags records the current value of every flag of every command.
+func snapshotFlags() map[*pflag.Flag]flagSnapshot {
+	snapshot := map[*pflag.Flag]flagSnapshot{}
+	record := func(f *pflag.Flag) {
+		snap := flagSnapshot{value: f.Value.String(), changed: f.Changed}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			snap.slice = sv.GetSlice()
+		}
+		snapshot[f] = snap
+	}
+	walkCommands(RootCmd, func(c *cobra.Command) {
+		c.Flags().VisitAll(record)
+		c.PersistentFlags().VisitAll(record)
+	})
+	return snapshot
+}
+
+// restoreFlags sets every flag back to its snapshot; flags cobra added since, like
+// --help, get their default. Slices are replaced since Set appends to them once set.
+func restoreFlags(snapshot map[*pflag.Flag]flagSnapshot) {
+	restore := func(f *pflag.Flag) {
+		snap, ok := snapshot[f]
+		if !ok {
+			snap = flagSnapshot{value: f.DefValue}
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(snap.slice)
+		} else {
+			_ = f.Value.Set(snap.value)
+		}
+		f.Changed = snap.changed
+	}
+	walkCommands(RootCmd, func(c *cobra.Command) {
+		c.Flags().VisitAll(restore)
+		c.PersistentFlags().VisitAll(restore)
+	})
+}
+
+// complete is the terminal's tab completion: commands for the first word, then flags,
+// namespaces after -n, or the names of what the command searches.
+func (s *shellSession) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+	head := line[:pos]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+	words := strings.Fields(head[:start])
+
+	var matches []string
+	seen := map[string]bool{}
+	for _, c := range s.candidates(words, word) {
+		if strings.HasPrefix(c, word) && !seen[c] {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+	sort.Strings(matches)
+
+	completion := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, completion) {
+			completion = completion[:len(completion)-1]
+		}
+	}
+	if len(matches) == 1 {
+		completion += " "
+	} else if completion == word {
+		// Nothing to add: list the choices above the prompt, like bash does.
+		fmt.Fprintln(s.terminal, strings.Join(matches, "  "))
+		return "", 0, false
+	}
+	return head[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// candidates returns what may stand in place of the word after words.
+func (s *shellSession) candidates(words []string, word string) []string {
+	if len(words) == 0 {
+		names := append([]string{}, shellBuiltins...)
+		for _, c := range RootCmd.Commands() {
+			if c.IsAvailableCommand() && c.Name() != "shell" {
+				names = append(names, c.Name())
+			}
+		}
+		return names
+	}
+	if words[0] == "ns" {
+		if len(words) == 1 {
+			return append(s.namespaceNames(), "-A")
+		}
+		return nil
+	}
+	if last := words[len(words)-1]; last == "-n" || last == "--namespace" {
+		return s.namespaceNames()
+	}
+
+	target, _, err := RootCmd.Find(words)
+	if err != nil || target == RootCmd {
+		return nil
+	}
+	if strings.HasPrefix(word, "-") {
+		var flags []string
+		target.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				flags = append(flags, "--"+f.Name)
+			}
+		})
+		target.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden {
+				flags = append(flags, "--"+f.Name)
+			}
+		})
+		return flags
+	}
+	switch target {
+	case svcCmd:
+		return s.resourceNames("services")
+	case nodehealthCmd, drainCmd, uncordonCmd:
+		return s.resourceNames("nodes")
+	}
+	return s.resourceNames("pods")
+}
+
+// namespaceNames lists the namespaces once per session.
+func (s *shellSession) namespaceNames() []string {
+	if s.namespaces == nil {
+		ctx, cancel := context.WithTimeout(s.ctx, shellCompletionTimeout)
+		defer cancel()
+		s.namespaces, _ = listNamespaceNames(ctx, configFlags)
+	}
+	return s.namespaces
+}
+
+// resourceNames lists pods, services or nodes of the current namespace, from the cache
+// when it is ready.
+func (s *shellSession) resourceNames(resource string) []string {
+	ctx, cancel := context.WithTimeout(s.ctx, shellCompletionTimeout)
+	defer cancel()
+
+	var names []string
+	switch resource {
+	case "services":
+		items, ok := daemonList[corev1.Service](configFlags, resource, s.namespace)
+		if !ok {
+			list, err := s.clientset.CoreV1().Services(s.namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil
+			}
+			items = list.Items
+		}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+	case "nodes":
+		items, ok := daemonList[corev1.Node](configFlags, resource, metav1.NamespaceAll)
+		if !ok {
+			list, err := s.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil
+			}
+			items = list.Items
+		}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+	default:
+		items, ok := daemonList[corev1.Pod](configFlags, resource, s.namespace)
+		if !ok {
+			list, err := s.clientset.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil
+			}
+			items = list.Items
+		}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
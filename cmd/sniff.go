@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// sniff command flags.
+var (
+	sniffImage     string
+	sniffContainer string
+	sniffWrite     string
+	sniffWireshark bool
+	sniffDuration  time.Duration
+	sniffMaxSize   string
+	sniffFilter    string
+)
+
+// sniffCmd captures the network traffic of a pod with tcpdump in an ephemeral container.
+var sniffCmd = &cobra.Command{
+	Use:   "sniff [SEARCH_PATTERN]",
+	Short: "Capture the traffic of the pod matching [SEARCH_PATTERN] with tcpdump into a pcap file or Wireshark.",
+	RunE:  runSniffFunc(configFlags),
+	// The capture may go to Wireshark on the same terminal.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(sniffCmd, "pods")
+	sniffCmd.Flags().StringVar(&sniffImage, "image", "nicolaka/netshoot:v0.13",
+		"Image of the ephemeral capture container, must contain tcpdump, timeout and head.")
+	sniffCmd.Flags().StringVarP(&sniffContainer, "container", "c", "",
+		"Container whose namespaces the capture container joins. Defaults to the pod's default container.")
+	sniffCmd.Flags().StringVarP(&sniffWrite, "write", "w", "",
+		"pcap file to write. Defaults to <pod>-<time>.pcap.")
+	sniffCmd.Flags().BoolVar(&sniffWireshark, "wireshark", false,
+		"Pipe the capture into a local Wireshark instead of a file.")
+	sniffCmd.Flags().DurationVar(&sniffDuration, "duration", 30*time.Second,
+		"Stop the capture after this long.")
+	sniffCmd.Flags().StringVar(&sniffMaxSize, "max-size", "50Mi",
+		"Stop the capture after this many bytes of pcap data, e.g. 10Mi.")
+	sniffCmd.Flags().StringVar(&sniffFilter, "filter", "",
+		`tcpdump filter expression, e.g. "port 8080".`)
+	addDryRunFlag(sniffCmd)
+}
+
+// runSniffFunc returns a function that attaches a capture container to the matching pod and
+// streams the pcap until the duration or size limit is reached, or Ctrl+C is pressed.
+func runSniffFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper sniff api\nor:\n  kubectl helper sniff -n dev api --filter \"port 8080\" --wireshark")
+		}
+		searchTerm := args[0]
+
+		if sniffWireshark && sniffWrite != "" {
+			return fmt.Errorf("--wireshark and --write can't be used together")
+		}
+		maxSize, err := resource.ParseQuantity(sniffMaxSize)
+		if err != nil {
+			return fmt.Errorf("invalid --max-size %q: %w", sniffMaxSize, err)
+		}
+		seconds := max(int(sniffDuration.Seconds()), 1)
+		dryRun, err := getDryRunStrategy()
+		if err != nil {
+			return err
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		// Ctrl+C ends the capture but still leaves a valid pcap behind.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		pod, err := findSinglePod(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return fmt.Errorf("pod %s is %s, only running pods can be captured", pod.Name, pod.Status.Phase)
+		}
+		target := sniffContainer
+		if target == "" {
+			target = defaultContainer(pod)
+		}
+
+		// The container only lives a little longer than the capture: ephemeral containers
+		// can't be removed, so letting them exit is the cleanup.
+		debug, err := addDebugContainer(ctx, clientset, pod, target, sniffImage,
+			[]string{"sleep", fmt.Sprintf("%d", seconds+30)}, dryRun)
+		if err != nil {
+			return err
+		}
+		// In a dry run there is no container to capture with.
+		if debug == "" {
+			return nil
+		}
+
+		out, finish, err := sniffOutput(pod)
+		if err != nil {
+			return err
+		}
+
+		filter := ""
+		if sniffFilter != "" {
+			filter = "'" + strings.ReplaceAll(sniffFilter, "'", `'\''`) + "'"
+		}
+		capture := fmt.Sprintf("timeout %d tcpdump -i any -U -w - %s | head -c %d", seconds, filter, maxSize.Value())
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Capturing on %s/%s for up to %s or %s (Ctrl+C to stop)...\n",
+			pod.Namespace, pod.Name, sniffDuration, sniffMaxSize)
+
+		counter := &byteCounter{w: out}
+		var stderr strings.Builder
+		err = streamExecInPod(ctx, configFlags, clientset, pod, debug, []string{"sh", "-c", capture}, counter, &stderr)
+		if finishErr := finish(); finishErr != nil && err == nil {
+			err = finishErr
+		}
+		// An interrupted or timed out capture is a normal way to end it.
+		if err != nil && ctx.Err() == nil && counter.n == 0 {
+			return fmt.Errorf("capture failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+
+		color.New(color.FgGreen).Fprintf(os.Stderr, "Captured %s of pcap data; capture container %s exits within %ds\n",
+			formatBytes(counter.n), debug, seconds+30)
+		return nil
+	}
+}
+
+// sniffOutput opens the destination of the pcap stream: Wireshark's stdin or a file.
+// finish closes it and, for Wireshark, leaves it running for the user.
+func sniffOutput(pod *corev1.Pod) (io.Writer, func() error, error) {
+	if sniffWireshark {
+		wireshark := exec.Command("wireshark", "-k", "-i", "-")
+		stdin, err := wireshark.StdinPipe()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := wireshark.Start(); err != nil {
+			return nil, nil, fmt.Errorf("failed to start wireshark: %w", err)
+		}
+		return stdin, stdin.Close, nil
+	}
+
+	path := sniffWrite
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.pcap", pod.Name, time.Now().Format("20060102-150405"))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create pcap file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Writing capture to %s\n", path)
+	return f, f.Close, nil
+}
+
+// byteCounter counts the bytes written through it.
+type byteCounter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// SpreadInfo holds how many pods of a workload run in one zone/node.
+type SpreadInfo struct {
+	Zone     string
+	NodeName string
+	Pods     int
+}
+
+// spreadWorkload groups the matched pods that belong to one workload.
+type spreadWorkload struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Pods      []corev1.Pod
+}
+
+// spreadCmd reports the zone and node distribution of the matching pods.
+var spreadCmd = &cobra.Command{
+	Use:   "spread [SEARCH_PATTERN]",
+	Short: "Show how pods containing [SEARCH_PATTERN] are spread over zones and nodes, per workload.",
+	RunE:  runSpreadFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(spreadCmd, "pods")
+}
+
+// runSpreadFunc returns a function that groups the matching pods per workload, zone and node
+// and checks them against their topologySpreadConstraints.
+func runSpreadFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper spread web\nor:\n  kubectl helper spread -n dev web")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve nodes: %w", err)
+		}
+		nodes := make(map[string]*corev1.Node, len(nodeList.Items))
+		clusterZones := map[string]bool{}
+		for i := range nodeList.Items {
+			node := &nodeList.Items[i]
+			nodes[node.Name] = node
+			if zone := nodeZone(node); zone != "" {
+				clusterZones[zone] = true
+			}
+		}
+
+		resolver, err := newOwnerResolver(configFlags)
+		if err != nil {
+			return err
+		}
+		for _, w := range groupByWorkload(ctx, resolver, pods) {
+			printSpreadReport(w, nodes, len(clusterZones))
+		}
+		return nil
+	}
+}
+
+// groupByWorkload groups pods by their controlling workload, sorted by namespace and name.
+func groupByWorkload(ctx context.Context, resolver *ownerResolver, pods []corev1.Pod) []*spreadWorkload {
+	byKey := map[string]*spreadWorkload{}
+	var workloads []*spreadWorkload
+	for _, pod := range pods {
+		kind, name := resolver.podWorkload(ctx, &pod)
+		key := pod.Namespace + "/" + kind + "/" + name
+		w, ok := byKey[key]
+		if !ok {
+			w = &spreadWorkload{Namespace: pod.Namespace, Kind: kind, Name: name}
+			byKey[key] = w
+			workloads = append(workloads, w)
+		}
+		w.Pods = append(w.Pods, pod)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	return workloads
+}
+
+// nodeZone returns the zone label of node, falling back to the deprecated beta label.
+func nodeZone(node *corev1.Node) string {
+	if zone, ok := node.Labels[corev1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+// spreadViolations checks the workload's topologySpreadConstraints against where its pods
+// actually run, and returns a description of every constraint whose skew is exceeded.
+func spreadViolations(w *spreadWorkload, nodes map[string]*corev1.Node) []string {
+	var violations []string
+	for _, c := range w.Pods[0].Spec.TopologySpreadConstraints {
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil {
+			continue
+		}
+
+		// Every node carrying the topology key is a domain, even if it has no pods.
+		counts := map[string]int{}
+		for _, node := range nodes {
+			if domain, ok := node.Labels[c.TopologyKey]; ok {
+				if _, seen := counts[domain]; !seen {
+					counts[domain] = 0
+				}
+			}
+		}
+		for _, pod := range w.Pods {
+			if pod.Spec.NodeName == "" || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			node, ok := nodes[pod.Spec.NodeName]
+			if !ok {
+				continue
+			}
+			if domain, ok := node.Labels[c.TopologyKey]; ok {
+				counts[domain]++
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+
+		lowest, highest := -1, 0
+		for _, n := range counts {
+			if lowest == -1 || n < lowest {
+				lowest = n
+			}
+			if n > highest {
+				highest = n
+			}
+		}
+		if skew := highest - lowest; skew > int(c.MaxSkew) {
+			violations = append(violations, fmt.Sprintf("topologySpreadConstraint on %s violated: skew %d > maxSkew %d (%s)",
+				c.TopologyKey, skew, c.MaxSkew, c.WhenUnsatisfiable))
+		}
+	}
+	return violations
+}
+
+// printSpreadReport prints the zone/node distribution of one workload and flags problems.
+func printSpreadReport(w *spreadWorkload, nodes map[string]*corev1.Node, clusterZoneCount int) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	warnColor := color.New(color.FgYellow, color.Bold)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	counts := map[[2]string]int{}
+	zones := map[string]int{}
+	pending := 0
+	for _, pod := range w.Pods {
+		if pod.Spec.NodeName == "" {
+			pending++
+			continue
+		}
+		zone := "<none>"
+		if node, ok := nodes[pod.Spec.NodeName]; ok && nodeZone(node) != "" {
+			zone = nodeZone(node)
+		}
+		counts[[2]string{zone, pod.Spec.NodeName}]++
+		zones[zone]++
+	}
+
+	var rows []SpreadInfo
+	for key, n := range counts {
+		rows = append(rows, SpreadInfo{Zone: key[0], NodeName: key[1], Pods: n})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Zone != rows[j].Zone {
+			return rows[i].Zone < rows[j].Zone
+		}
+		return rows[i].NodeName < rows[j].NodeName
+	})
+
+	fmt.Println()
+	headerColor.Printf("%s/%s %s: %d pod(s) in %d zone(s) on %d node(s)\n",
+		w.Namespace, strings.ToLower(w.Kind), w.Name, len(w.Pods), len(zones), len(rows))
+	headerColor.Printf("%-25s %-40s %-6s\n", "ZONE", "NODE NAME", "PODS")
+	line := strings.Repeat("-", 75)
+	lineColor.Println(line)
+	for _, r := range rows {
+		fmt.Printf("%-25s %-40s %-6d\n", r.Zone, r.NodeName, r.Pods)
+	}
+	if pending > 0 {
+		fmt.Printf("%-25s %-40s %-6d\n", "<none>", "<not scheduled>", pending)
+	}
+
+	scheduled := len(w.Pods) - pending
+	if scheduled > 1 && len(zones) == 1 && clusterZoneCount > 1 {
+		for zone := range zones {
+			warnColor.Printf("! all %d scheduled pods run in a single zone (%s) although the cluster has %d zones\n", scheduled, zone, clusterZoneCount)
+		}
+	}
+	if scheduled > 1 && len(rows) == 1 {
+		warnColor.Printf("! all %d scheduled pods run on a single node (%s)\n", scheduled, rows[0].NodeName)
+	}
+	for _, v := range spreadViolations(w, nodes) {
+		errorColor.Printf("! %s\n", v)
+	}
+	fmt.Println()
+}
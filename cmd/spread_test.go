@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testNodes returns nodes by name, each in the zone given for it; "" leaves the zone unset.
+func testNodes(zones map[string]string) map[string]*corev1.Node {
+	nodes := map[string]*corev1.Node{}
+	for name, zone := range zones {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelHostname: name}}}
+		if zone != "" {
+			node.Labels[corev1.LabelTopologyZone] = zone
+		}
+		nodes[name] = node
+	}
+	return nodes
+}
+
+// testWorkload returns a workload with one pod labeled app=web per node name, sharing spec.
+func testWorkload(spec corev1.PodSpec, nodeNames ...string) *spreadWorkload {
+	w := &spreadWorkload{Namespace: "default", Kind: "Deployment", Name: "web"}
+	for _, node := range nodeNames {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-" + node, Namespace: "default", Labels: map[string]string{"app": "web"}},
+			Spec:       *spec.DeepCopy(),
+		}
+		pod.Spec.NodeName = node
+		w.Pods = append(w.Pods, pod)
+	}
+	return w
+}
+
+func TestNodeZone(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{
+		{"zone label", map[string]string{corev1.LabelTopologyZone: "a"}, "a"},
+		{"beta label", map[string]string{corev1.LabelFailureDomainBetaZone: "b"}, "b"},
+		{"both prefer GA", map[string]string{corev1.LabelTopologyZone: "a", corev1.LabelFailureDomainBetaZone: "b"}, "a"},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			if got := nodeZone(node); got != tt.want {
+				t.Errorf("nodeZone() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpreadViolations(t *testing.T) {
+	zoneSpread := func(maxSkew int32) corev1.PodSpec {
+		return corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew:           maxSkew,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}}}
+	}
+	nodes := testNodes(map[string]string{"n1": "a", "n2": "a", "n3": "b", "n4": "c"})
+
+	tests := []struct {
+		name     string
+		workload *spreadWorkload
+		want     []string
+	}{
+		{
+			name:     "no constraints",
+			workload: testWorkload(corev1.PodSpec{}, "n1", "n2"),
+		},
+		{
+			name:     "within skew",
+			workload: testWorkload(zoneSpread(1), "n1", "n3", "n4"),
+		},
+		{
+			// Zone c has no pods but still counts as a domain.
+			name:     "empty zone counts",
+			workload: testWorkload(zoneSpread(1), "n1", "n2", "n3"),
+			want:     []string{"topologySpreadConstraint on topology.kubernetes.io/zone violated: skew 2 > maxSkew 1 (DoNotSchedule)"},
+		},
+		{
+			name:     "larger max skew",
+			workload: testWorkload(zoneSpread(2), "n1", "n2", "n3"),
+		},
+		{
+			name:     "unscheduled pods are ignored",
+			workload: testWorkload(zoneSpread(1), "n1", "", "", "n3", "n4"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spreadViolations(tt.workload, nodes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("spreadViolations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// StsVolumeInfo holds one StatefulSet pod <-> PVC pairing we want to display.
+type StsVolumeInfo struct {
+	StatefulSet  string
+	Namespace    string
+	Ordinal      int
+	Pod          string
+	PVC          string
+	Phase        string
+	PV           string
+	StorageClass string
+	NodeName     string
+}
+
+// stsCmd lists StatefulSet pods together with the volumes bound to them.
+var stsCmd = &cobra.Command{
+	Use:   "sts [SEARCH_PATTERN]",
+	Short: "List StatefulSet pods containing [SEARCH_PATTERN] with their ordinal, PVCs, PVs, storage class and node.",
+	RunE:  runStsFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(stsCmd, "StatefulSets")
+}
+
+// runStsFunc returns a function that maps every pod of the matching StatefulSets
+// to the PVCs it mounts.
+func runStsFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper sts postgres\nor:\n  kubectl helper sts -n dev postgres")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		statefulSets, err := listAcrossNamespaces(configFlags, func(ns string) ([]appsv1.StatefulSet, error) {
+			list, err := clientset.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve statefulsets: %w", err)
+		}
+
+		var matching []appsv1.StatefulSet
+		for _, sts := range statefulSets {
+			if matchesPattern(sts.Name, searchTerm) {
+				matching = append(matching, sts)
+			}
+		}
+		if len(matching) == 0 {
+			fmt.Printf("No statefulsets found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// PVs are cluster scoped, so a single list covers every StatefulSet.
+		pvList, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve persistentvolumes: %w", err)
+		}
+		pvs := make(map[string]corev1.PersistentVolume, len(pvList.Items))
+		for _, pv := range pvList.Items {
+			pvs[pv.Name] = pv
+		}
+
+		// Pods and PVCs are fetched once per namespace and shared by the StatefulSets in it.
+		podsByNamespace := map[string]map[string]corev1.Pod{}
+		pvcsByNamespace := map[string]map[string]corev1.PersistentVolumeClaim{}
+
+		var rows []StsVolumeInfo
+		for _, sts := range matching {
+			if _, ok := podsByNamespace[sts.Namespace]; !ok {
+				podList, err := clientset.CoreV1().Pods(sts.Namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to retrieve pods: %w", err)
+				}
+				pods := make(map[string]corev1.Pod, len(podList.Items))
+				for _, pod := range podList.Items {
+					pods[pod.Name] = pod
+				}
+				podsByNamespace[sts.Namespace] = pods
+
+				pvcList, err := clientset.CoreV1().PersistentVolumeClaims(sts.Namespace).List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to retrieve persistentvolumeclaims: %w", err)
+				}
+				pvcs := make(map[string]corev1.PersistentVolumeClaim, len(pvcList.Items))
+				for _, pvc := range pvcList.Items {
+					pvcs[pvc.Name] = pvc
+				}
+				pvcsByNamespace[sts.Namespace] = pvcs
+			}
+
+			rows = append(rows, stsVolumeRows(sts, podsByNamespace[sts.Namespace], pvcsByNamespace[sts.Namespace], pvs)...)
+		}
+
+		printStsTable(rows)
+		return nil
+	}
+}
+
+// stsVolumeRows builds one row per (ordinal, claim) of a StatefulSet. Pods follow the
+// "<sts>-<ordinal>" naming and their template claims the "<template>-<sts>-<ordinal>" one,
+// so claims are still listed when the pod itself does not exist (yet).
+func stsVolumeRows(sts appsv1.StatefulSet, pods map[string]corev1.Pod, pvcs map[string]corev1.PersistentVolumeClaim, pvs map[string]corev1.PersistentVolume) []StsVolumeInfo {
+	replicas := 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
+	}
+	start := 0
+	if sts.Spec.Ordinals != nil {
+		start = int(sts.Spec.Ordinals.Start)
+	}
+
+	var rows []StsVolumeInfo
+	for ordinal := start; ordinal < start+replicas; ordinal++ {
+		podName := fmt.Sprintf("%s-%d", sts.Name, ordinal)
+		pod, podExists := pods[podName]
+
+		var claimNames []string
+		if podExists {
+			for _, vol := range pod.Spec.Volumes {
+				if vol.PersistentVolumeClaim != nil {
+					claimNames = append(claimNames, vol.PersistentVolumeClaim.ClaimName)
+				}
+			}
+		} else {
+			for _, tpl := range sts.Spec.VolumeClaimTemplates {
+				claimNames = append(claimNames, fmt.Sprintf("%s-%s", tpl.Name, podName))
+			}
+		}
+
+		base := StsVolumeInfo{
+			StatefulSet: sts.Name,
+			Namespace:   sts.Namespace,
+			Ordinal:     ordinal,
+			Pod:         podName,
+			NodeName:    "<none>",
+		}
+		if !podExists {
+			base.Pod = podName + " (missing)"
+		} else if pod.Spec.NodeName != "" {
+			base.NodeName = pod.Spec.NodeName
+		}
+
+		if len(claimNames) == 0 {
+			base.PVC = "<none>"
+			rows = append(rows, base)
+			continue
+		}
+
+		for _, claimName := range claimNames {
+			row := base
+			row.PVC = claimName
+			row.PV = "<none>"
+			row.StorageClass = "<none>"
+
+			pvc, ok := pvcs[claimName]
+			if !ok {
+				row.Phase = "Missing"
+				rows = append(rows, row)
+				continue
+			}
+			row.Phase = string(pvc.Status.Phase)
+			if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+				row.StorageClass = *pvc.Spec.StorageClassName
+			}
+			if pvc.Spec.VolumeName != "" {
+				row.PV = pvc.Spec.VolumeName
+				// Statically provisioned volumes may only carry the class on the PV.
+				if pv, ok := pvs[pvc.Spec.VolumeName]; ok && row.StorageClass == "<none>" && pv.Spec.StorageClassName != "" {
+					row.StorageClass = pv.Spec.StorageClassName
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// printStsTable prints the StatefulSet volume table, highlighting claims that are not Bound.
+func printStsTable(rows []StsVolumeInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	pendingColor := color.New(color.FgYellow, color.Bold)
+	missingColor := color.New(color.FgRed, color.Bold)
+
+	fmt.Println()
+	headerColor.Printf("%-25s %-15s %-8s %-30s %-35s %-10s %-42s %-15s %-20s\n",
+		"STATEFULSET", "NAMESPACE", "ORDINAL", "POD", "PVC", "STATUS", "PV", "STORAGECLASS", "NODE NAME")
+
+	line := strings.Repeat("-", 210)
+	lineColor.Println(line)
+
+	pending := 0
+	for _, r := range rows {
+		fmt.Printf("%-25s %-15s %-8d %-30s %-35s ", r.StatefulSet, r.Namespace, r.Ordinal, r.Pod, r.PVC)
+		// Pad before coloring so the escape codes don't break the column alignment.
+		status := fmt.Sprintf("%-10s", r.Phase)
+		switch corev1.PersistentVolumeClaimPhase(r.Phase) {
+		case corev1.ClaimPending:
+			pending++
+			pendingColor.Print(status)
+		case corev1.ClaimBound, "":
+			fmt.Print(status)
+		default:
+			// Lost, or "Missing" when the claim doesn't exist at all.
+			missingColor.Print(status)
+		}
+		fmt.Printf(" %-42s %-15s %-20s\n", r.PV, r.StorageClass, r.NodeName)
+	}
+	fmt.Println()
+
+	if pending > 0 {
+		pendingColor.Printf("%d PVC(s) stuck in Pending\n\n", pending)
+	}
+}
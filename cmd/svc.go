@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ServiceInfo holds the essential Service data we want to display.
+type ServiceInfo struct {
+	Name           string
+	Namespace      string
+	Type           string
+	ClusterIP      string
+	Ports          string
+	ReadyEndpoints int
+	TotalEndpoints int
+}
+
+// EndpointIssue describes one mismatch between a Service's pods and its endpoints.
+type EndpointIssue struct {
+	Service   string
+	Namespace string
+	Pod       string
+	IP        string
+	Issue     string
+}
+
+// svcCheck enables the selector vs. EndpointSlice consistency check.
+var svcCheck bool
+
+// svcCmd lists Services by partial name match along with their endpoints.
+var svcCmd = &cobra.Command{
+	Use:   "svc [SEARCH_PATTERN]",
+	Short: "List services containing [SEARCH_PATTERN] in their name, along with their endpoints and OpenShift Routes.",
+	RunE:  runSvcFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(svcCmd, "services")
+	svcCmd.Flags().BoolVar(&svcCheck, "check", false,
+		"Compare the pods matching each service selector against its ready endpoints and report mismatches.")
+}
+
+// runSvcFunc returns a function that lists the matching services and, with --check,
+// verifies their endpoints.
+func runSvcFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper svc nginx\nor:\n  kubectl helper svc -n dev --check nginx")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		services, err := listAcrossNamespaces(configFlags, func(ns string) ([]corev1.Service, error) {
+			if cached, ok := daemonList[corev1.Service](configFlags, "services", ns); ok {
+				return cached, nil
+			}
+			list, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to retrieve services: %w", err)
+		}
+
+		var infos []ServiceInfo
+		var issues []EndpointIssue
+		for _, svc := range services {
+			if !matchesPattern(svc.Name, searchTerm) {
+				continue
+			}
+
+			slices, err := serviceEndpointSlices(cmd, clientset, &svc)
+			if err != nil {
+				return err
+			}
+			info := ServiceInfo{
+				Name:      svc.Name,
+				Namespace: svc.Namespace,
+				Type:      string(svc.Spec.Type),
+				ClusterIP: svc.Spec.ClusterIP,
+				Ports:     formatServicePorts(svc.Spec.Ports),
+			}
+			for _, slice := range slices {
+				for _, ep := range slice.Endpoints {
+					info.TotalEndpoints++
+					if endpointReady(ep) {
+						info.ReadyEndpoints++
+					}
+				}
+			}
+			infos = append(infos, info)
+
+			if svcCheck {
+				svcIssues, err := checkServiceEndpoints(cmd, clientset, &svc, slices)
+				if err != nil {
+					return err
+				}
+				issues = append(issues, svcIssues...)
+			}
+		}
+
+		if len(infos) == 0 {
+			fmt.Printf("No services found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		// On OpenShift, also show the Routes exposing the services. They only add to the
+		// service table, so users who may not list them still get it.
+		routes, err := findServiceRoutes(ctx, configFlags, clientset, infos)
+		if err != nil {
+			color.New(color.FgYellow).Fprintf(os.Stderr, "Not showing OpenShift Routes: %v\n", err)
+			routes = nil
+		}
+
+		printServiceTable(infos)
+		if len(routes) > 0 {
+			printRouteTable(routes)
+		}
+		if svcCheck {
+			printEndpointIssues(issues)
+		}
+		return nil
+	}
+}
+
+// serviceEndpointSlices returns the EndpointSlices managed for svc.
+func serviceEndpointSlices(cmd *cobra.Command, clientset kubernetes.Interface, svc *corev1.Service) ([]discoveryv1.EndpointSlice, error) {
+	list, err := clientset.DiscoveryV1().EndpointSlices(svc.Namespace).List(cmd.Context(), metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + svc.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve endpointslices: %w", err)
+	}
+	return list.Items, nil
+}
+
+// endpointReady treats a missing ready condition as ready, as the EndpointSlice API requires.
+func endpointReady(ep discoveryv1.Endpoint) bool {
+	return ep.Conditions.Ready == nil || *ep.Conditions.Ready
+}
+
+// checkServiceEndpoints compares the pods selected by svc against its EndpointSlices and reports
+// running pods that don't receive traffic and endpoints that point at pods which are gone.
+func checkServiceEndpoints(cmd *cobra.Command, clientset kubernetes.Interface, svc *corev1.Service, slices []discoveryv1.EndpointSlice) ([]EndpointIssue, error) {
+	// Services without a selector have manually managed endpoints; there's nothing to compare.
+	if len(svc.Spec.Selector) == 0 {
+		return nil, nil
+	}
+
+	podList, err := clientset.CoreV1().Pods(svc.Namespace).List(cmd.Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pods: %w", err)
+	}
+	pods := make(map[string]*corev1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pods[podList.Items[i].Name] = &podList.Items[i]
+	}
+
+	readyPods := map[string]bool{}
+	var issues []EndpointIssue
+	newIssue := func(pod, ip, issue string) EndpointIssue {
+		return EndpointIssue{Service: svc.Name, Namespace: svc.Namespace, Pod: pod, IP: ip, Issue: issue}
+	}
+
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			ip := strings.Join(ep.Addresses, ",")
+			if ep.TargetRef == nil || ep.TargetRef.Kind != "Pod" {
+				continue
+			}
+			if endpointReady(ep) {
+				readyPods[ep.TargetRef.Name] = true
+			}
+
+			pod, ok := pods[ep.TargetRef.Name]
+			switch {
+			case !ok:
+				issues = append(issues, newIssue(ep.TargetRef.Name, ip, "endpoint points at a pod that no longer exists"))
+			case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed:
+				issues = append(issues, newIssue(pod.Name, ip, fmt.Sprintf("endpoint points at a terminated pod (%s)", pod.Status.Phase)))
+			case pod.DeletionTimestamp != nil && endpointReady(ep):
+				issues = append(issues, newIssue(pod.Name, ip, "ready endpoint points at a terminating pod"))
+			}
+		}
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || readyPods[pod.Name] {
+			continue
+		}
+		if !isPodReady(&pod) {
+			issues = append(issues, newIssue(pod.Name, pod.Status.PodIP, "running but not receiving traffic (readiness failing)"))
+		} else {
+			issues = append(issues, newIssue(pod.Name, pod.Status.PodIP, "running and ready but missing from endpoints"))
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Pod < issues[j].Pod })
+	return issues, nil
+}
+
+// formatServicePorts renders ports like kubectl does, e.g. "80/TCP,443:30443/TCP".
+func formatServicePorts(ports []corev1.ServicePort) string {
+	if len(ports) == 0 {
+		return "<none>"
+	}
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		if p.NodePort != 0 {
+			parts = append(parts, fmt.Sprintf("%d:%d/%s", p.Port, p.NodePort, p.Protocol))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// printServiceTable prints the table of matching services.
+func printServiceTable(services []ServiceInfo) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+
+	fmt.Println()
+	headerColor.Printf("%-30s %-20s %-15s %-20s %-25s %-10s\n", "NAME", "NAMESPACE", "TYPE", "CLUSTER IP", "PORTS", "ENDPOINTS")
+
+	line := strings.Repeat("-", 125)
+	lineColor.Println(line)
+
+	for _, s := range services {
+		endpoints := fmt.Sprintf("%d/%d", s.ReadyEndpoints, s.TotalEndpoints)
+		fmt.Printf("%-30s %-20s %-15s %-20s %-25s %-10s\n", s.Name, s.Namespace, s.Type, s.ClusterIP, s.Ports, endpoints)
+	}
+	fmt.Println()
+}
+
+// printEndpointIssues prints the result of the --check comparison.
+func printEndpointIssues(issues []EndpointIssue) {
+	if len(issues) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("All selected pods match their ready endpoints.")
+		fmt.Println()
+		return
+	}
+
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+	issueColor := color.New(color.FgRed)
+
+	headerColor.Printf("%-30s %-20s %-30s %-20s %-40s\n", "SERVICE", "NAMESPACE", "POD", "IP", "ISSUE")
+	line := strings.Repeat("-", 145)
+	lineColor.Println(line)
+
+	for _, i := range issues {
+		fmt.Printf("%-30s %-20s %-30s %-20s ", i.Service, i.Namespace, i.Pod, i.IP)
+		issueColor.Println(i.Issue)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// table sizes its columns from the data instead of fixed %-30s formats, so long names
+// and wide unicode characters don't push the following columns out of line.
+type table struct {
+	headers []string
+	rows    [][]string
+	// colors holds the color of individual cells, keyed by row and column.
+	colors map[[2]int]*color.Color
+	// maxWidth truncates cells wider than it with an ellipsis; 0 means no limit.
+	maxWidth int
+}
+
+// newTable creates a table with the given column headers.
+func newTable(headers ...string) *table {
+	return &table{headers: headers, colors: map[[2]int]*color.Color{}}
+}
+
+// addRow appends a row; missing trailing cells are left empty.
+func (t *table) addRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// colorCell colors a cell of the last added row.
+func (t *table) colorCell(col int, c *color.Color) {
+	t.colors[[2]int{len(t.rows) - 1, col}] = c
+}
+
+// cell returns the text of a cell, truncated to maxWidth.
+func (t *table) cell(cells []string, col int) string {
+	if col >= len(cells) {
+		return ""
+	}
+	if t.maxWidth > 0 && runewidth.StringWidth(cells[col]) > t.maxWidth {
+		return runewidth.Truncate(cells[col], t.maxWidth, "…")
+	}
+	return cells[col]
+}
+
+// print writes the table in the usual style: a bold cyan header, a cyan separator
+// line, then the rows, with a blank line before and after.
+func (t *table) print(out io.Writer) {
+	headerColor := color.New(color.FgCyan, color.Bold)
+	lineColor := color.New(color.FgCyan)
+
+	widths := make([]int, len(t.headers))
+	for col := range t.headers {
+		widths[col] = runewidth.StringWidth(t.cell(t.headers, col))
+		for _, row := range t.rows {
+			widths[col] = max(widths[col], runewidth.StringWidth(t.cell(row, col)))
+		}
+	}
+	total := len(widths) - 1
+	for _, w := range widths {
+		total += w
+	}
+
+	// Cells are padded before they are colored, so escape codes don't count as width.
+	pad := func(text string, col int) string {
+		if col == len(widths)-1 {
+			return text
+		}
+		return runewidth.FillRight(text, widths[col])
+	}
+
+	fmt.Fprintln(out)
+	header := make([]string, len(t.headers))
+	for col := range t.headers {
+		header[col] = pad(t.cell(t.headers, col), col)
+	}
+	headerColor.Fprintln(out, strings.Join(header, " "))
+	lineColor.Fprintln(out, strings.Repeat("-", total))
+	for i, row := range t.rows {
+		for col := range t.headers {
+			if col > 0 {
+				fmt.Fprint(out, " ")
+			}
+			text := pad(t.cell(row, col), col)
+			if c, ok := t.colors[[2]int{i, col}]; ok {
+				c.Fprint(out, text)
+			} else {
+				fmt.Fprint(out, text)
+			}
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+}
+
+// printMarkdown writes the table as a GitHub-flavored Markdown table, without colors,
+// e.g. for tickets and postmortems. Columns are padded so the source reads well too.
+func (t *table) printMarkdown(out io.Writer) {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+	rows := make([][]string, 0, len(t.rows)+1)
+	for _, row := range append([][]string{t.headers}, t.rows...) {
+		cells := make([]string, len(t.headers))
+		for col := range t.headers {
+			cells[col] = escape.Replace(t.cell(row, col))
+		}
+		rows = append(rows, cells)
+	}
+
+	// The separator needs at least three dashes.
+	widths := make([]int, len(t.headers))
+	for col := range widths {
+		widths[col] = 3
+		for _, row := range rows {
+			widths[col] = max(widths[col], runewidth.StringWidth(row[col]))
+		}
+	}
+	writeRow := func(cells []string) {
+		for col, text := range cells {
+			fmt.Fprint(out, "| ", runewidth.FillRight(text, widths[col]), " ")
+		}
+		fmt.Fprintln(out, "|")
+	}
+
+	writeRow(rows[0])
+	separator := make([]string, len(widths))
+	for col, w := range widths {
+		separator[col] = strings.Repeat("-", w)
+	}
+	writeRow(separator)
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// withoutColor disables escape codes for the duration of a test.
+func withoutColor(t *testing.T) {
+	t.Helper()
+	saved := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = saved })
+}
+
+func TestTablePrint(t *testing.T) {
+	withoutColor(t)
+	tests := []struct {
+		name     string
+		maxWidth int
+		rows     [][]string
+		want     string
+	}{
+		{
+			name: "columns sized by the widest cell",
+			rows: [][]string{{"web-1", "default", "10.0.0.1"}, {"a", "kube-system", "10.0.0.22"}},
+			want: "\n" +
+				"NAME  NAMESPACE   IP\n" +
+				"---------------------------\n" +
+				"web-1 default     10.0.0.1\n" +
+				"a     kube-system 10.0.0.22\n\n",
+		},
+		{
+			name: "wide characters",
+			rows: [][]string{{"日本", "x", "y"}},
+			want: "\n" +
+				"NAME NAMESPACE IP\n" +
+				"-----------------\n" +
+				"日本 x         y\n\n",
+		},
+		{
+			name: "missing cells",
+			rows: [][]string{{"web"}},
+			want: "\n" +
+				"NAME NAMESPACE IP\n" +
+				"-----------------\n" +
+				"web            \n\n",
+		},
+		{
+			name:     "truncated to max width",
+			maxWidth: 6,
+			rows:     [][]string{{"very-long-pod-name", "default", "10.0.0.1"}},
+			want: "\n" +
+				"NAME   NAMES… IP\n" +
+				"--------------------\n" +
+				"very-… defau… 10.0.…\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tbl := newTable("NAME", "NAMESPACE", "IP")
+			tbl.maxWidth = tt.maxWidth
+			for _, row := range tt.rows {
+				tbl.addRow(row...)
+			}
+			var out bytes.Buffer
+			tbl.print(&out)
+			if out.String() != tt.want {
+				t.Errorf("print() =\n%q\nwant\n%q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTablePrintMarkdown(t *testing.T) {
+	tbl := newTable("NAME", "NOTE")
+	tbl.addRow("a|b", "two\nlines")
+	tbl.addRow("c")
+	var out bytes.Buffer
+	tbl.printMarkdown(&out)
+
+	want := strings.Join([]string{
+		`| NAME | NOTE      |`,
+		`| ---- | --------- |`,
+		`| a\|b | two lines |`,
+		`| c    |           |`,
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Errorf("printMarkdown() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// namedTemplate returns the output spec stored under name in the templates section of
+// the config file, e.g. "custom-columns=NAME:.name,IP:.ip".
+func namedTemplate(name string) (string, error) {
+	spec, ok := helperConfig.Templates[name]
+	if !ok {
+		var names []string
+		for n := range helperConfig.Templates {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", fmt.Errorf("unknown template %q, no templates are defined in the config file", name)
+		}
+		return "", fmt.Errorf("unknown template %q, must be one of: %s", name, strings.Join(names, ", "))
+	}
+	if !isTemplateFormat(spec) {
+		return "", fmt.Errorf("template %q must start with go-template= or custom-columns=", name)
+	}
+	return spec, nil
+}
+
+// isTemplateFormat reports whether an -o value is a go-template or custom-columns spec.
+func isTemplateFormat(format string) bool {
+	return strings.HasPrefix(format, "go-template=") || strings.HasPrefix(format, "custom-columns=")
+}
+
+// templateFields converts v to the generic map its JSON form decodes to, so templates and
+// column paths use the same lowercase field names as -o json (.name, .ip, .nodeName, ...).
+func templateFields(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// printTemplate renders items with a "go-template=..." or "custom-columns=..." spec.
+// A go-template is executed once per item; custom columns become an aligned table.
+func printTemplate(out io.Writer, spec string, items interface{}) error {
+	fields, err := templateFields(items)
+	if err != nil {
+		return fmt.Errorf("failed to prepare template data: %w", err)
+	}
+	list, _ := fields.([]interface{})
+
+	if text, ok := strings.CutPrefix(spec, "go-template="); ok {
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %w", err)
+		}
+		for _, item := range list {
+			if err := tmpl.Execute(out, item); err != nil {
+				return fmt.Errorf("failed to execute go-template: %w", err)
+			}
+		}
+		return nil
+	}
+
+	columnSpec, ok := strings.CutPrefix(spec, "custom-columns=")
+	if !ok {
+		return fmt.Errorf("unknown template kind in %q, must start with go-template= or custom-columns=", spec)
+	}
+	var headers []string
+	var paths []*jsonpath.JSONPath
+	for _, column := range strings.Split(columnSpec, ",") {
+		header, path, found := strings.Cut(column, ":")
+		if !found {
+			return fmt.Errorf("invalid custom column %q, expected HEADER:.path", column)
+		}
+		jp := jsonpath.New(header).AllowMissingKeys(true)
+		if err := jp.Parse(fmt.Sprintf("{%s}", path)); err != nil {
+			return fmt.Errorf("invalid path in custom column %q: %w", column, err)
+		}
+		headers = append(headers, header)
+		paths = append(paths, jp)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, item := range list {
+		values := make([]string, len(paths))
+		for i, jp := range paths {
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, item); err != nil {
+				return fmt.Errorf("failed to evaluate custom column %s: %w", headers[i], err)
+			}
+			values[i] = buf.String()
+			if values[i] == "" {
+				values[i] = "<none>"
+			}
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
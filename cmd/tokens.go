@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TokenInfo holds one ServiceAccount token volume of a matched pod.
+type TokenInfo struct {
+	Pod       string
+	Namespace string
+	Volume    string
+	// Legacy is true for a long-lived token Secret instead of a projected token.
+	Legacy bool
+	// Secret is the name of the legacy token Secret.
+	Secret   string
+	Audience string
+	// Expiration is the requested lifetime of a projected token.
+	Expiration time.Duration
+	// Mounts are the container:path pairs the volume is mounted at.
+	Mounts []string
+}
+
+// The API server defaults projected tokens to one hour. kube-api-access volumes ask for
+// 3607s, which API servers extend to a year unless --service-account-extend-token-expiration
+// is turned off, so clients relying on legacy tokens keep working.
+const (
+	defaultTokenExpiration  = time.Hour
+	extendedTokenExpiration = 3607 * time.Second
+)
+
+// tokensLegacyOnly keeps only the legacy token Secrets.
+var tokensLegacyOnly bool
+
+// tokensCmd lists the ServiceAccount token volumes of the matching pods.
+var tokensCmd = &cobra.Command{
+	Use:   "tokens [SEARCH_PATTERN]",
+	Short: "List the projected ServiceAccount token volumes of pods containing [SEARCH_PATTERN] with expiry and audience, and flag legacy token Secrets.",
+	RunE:  runTokensFunc(configFlags),
+}
+
+func init() {
+	addNamespaceFlag(tokensCmd, "pods")
+	tokensCmd.Flags().BoolVar(&tokensLegacyOnly, "legacy-only", false,
+		"Only show pods mounting legacy long-lived token Secrets.")
+}
+
+// runTokensFunc returns a function that prints one row per token volume of the matching pods.
+func runTokensFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper tokens api\nor:\n  kubectl helper tokens -A --legacy-only \"\"")
+		}
+		searchTerm := args[0]
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+
+		pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+		if len(pods) == 0 {
+			fmt.Printf("No pods found matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		secretTypes := map[string]corev1.SecretType{}
+		var rows []TokenInfo
+		legacyPods := map[string]bool{}
+		for i := range pods {
+			pod := &pods[i]
+			infos, err := podTokenInfos(ctx, clientset, pod, secretTypes)
+			if err != nil {
+				return err
+			}
+			for _, info := range infos {
+				if info.Legacy {
+					legacyPods[pod.Namespace+"/"+pod.Name] = true
+				} else if tokensLegacyOnly {
+					continue
+				}
+				rows = append(rows, info)
+			}
+		}
+		historyMatches = len(rows)
+		if len(rows) == 0 {
+			fmt.Printf("No token volumes found in pods matching the pattern: %s\n", searchTerm)
+			return nil
+		}
+
+		printTokenTable(rows)
+		if len(legacyPods) > 0 {
+			color.New(color.FgRed, color.Bold).Printf("%d pod(s) still mount legacy long-lived token Secrets; switch them to projected tokens.\n\n", len(legacyPods))
+		}
+		return nil
+	}
+}
+
+// podTokenInfos returns the token volumes of pod. secretTypes caches the type of the
+// Secrets looked at, keyed by namespace/name. The tokens and sa commands both read the
+// tokens of a pod through it.
+func podTokenInfos(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod, secretTypes map[string]corev1.SecretType) ([]TokenInfo, error) {
+	var infos []TokenInfo
+	for _, vol := range pod.Spec.Volumes {
+		info := TokenInfo{Pod: pod.Name, Namespace: pod.Namespace, Volume: vol.Name, Mounts: volumeMounts(pod, vol.Name)}
+		switch {
+		case vol.Projected != nil:
+			for _, src := range vol.Projected.Sources {
+				if src.ServiceAccountToken == nil {
+					continue
+				}
+				info.Audience = src.ServiceAccountToken.Audience
+				info.Expiration = defaultTokenExpiration
+				if src.ServiceAccountToken.ExpirationSeconds != nil {
+					info.Expiration = time.Duration(*src.ServiceAccountToken.ExpirationSeconds) * time.Second
+				}
+				infos = append(infos, info)
+			}
+		case vol.Secret != nil:
+			legacy, err := isTokenSecret(ctx, clientset, pod.Namespace, vol.Secret.SecretName, secretTypes)
+			if err != nil {
+				return nil, err
+			}
+			if legacy {
+				info.Legacy = true
+				info.Secret = vol.Secret.SecretName
+				infos = append(infos, info)
+			}
+		}
+	}
+	return infos, nil
+}
+
+// isTokenSecret reports whether a Secret holds a legacy ServiceAccount token. Without the
+// permission to read it, the <sa>-token-<suffix> naming of the token controller decides.
+func isTokenSecret(ctx context.Context, clientset kubernetes.Interface, namespace, name string, secretTypes map[string]corev1.SecretType) (bool, error) {
+	key := namespace + "/" + name
+	secretType, ok := secretTypes[key]
+	if !ok {
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsForbidden(err), apierrors.IsNotFound(err):
+			secretType = ""
+		case err != nil:
+			return false, fmt.Errorf("failed to retrieve secret %s: %w", name, err)
+		default:
+			secretType = secret.Type
+		}
+		secretTypes[key] = secretType
+	}
+	if secretType == "" {
+		return strings.Contains(name, "-token-"), nil
+	}
+	return secretType == corev1.SecretTypeServiceAccountToken, nil
+}
+
+// tokenExpirationText describes how long a token lives, e.g. "60m (extended to 1y)".
+func tokenExpirationText(info TokenInfo) string {
+	if info.Legacy {
+		return "never"
+	}
+	text := duration.HumanDuration(info.Expiration)
+	if info.Expiration == extendedTokenExpiration {
+		text += " (extended to 1y)"
+	}
+	return text
+}
+
+// volumeMounts returns where the containers of pod mount the volume, as container:path.
+func volumeMounts(pod *corev1.Pod, volume string) []string {
+	var mounts []string
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			for _, m := range c.VolumeMounts {
+				if m.Name == volume {
+					mounts = append(mounts, c.Name+":"+m.MountPath)
+				}
+			}
+		}
+	}
+	return mounts
+}
+
+// printTokenTable prints a row per token volume; legacy Secrets are red, extended tokens yellow.
+func printTokenTable(rows []TokenInfo) {
+	warnColor := color.New(color.FgYellow)
+	errorColor := color.New(color.FgRed, color.Bold)
+
+	t := newTable("POD", "NAMESPACE", "VOLUME", "TYPE", "AUDIENCE", "EXPIRATION", "MOUNTED AT")
+	for _, r := range rows {
+		kind, audience, expiration := "projected", r.Audience, tokenExpirationText(r)
+		if audience == "" || r.Legacy {
+			audience = "<api server>"
+		}
+		if r.Legacy {
+			kind = "legacy secret " + r.Secret
+		}
+		mounts := "-"
+		if len(r.Mounts) > 0 {
+			mounts = strings.Join(r.Mounts, ",")
+		}
+		t.addRow(r.Pod, r.Namespace, r.Volume, kind, audience, expiration, mounts)
+
+		switch {
+		case r.Legacy:
+			t.colorCell(3, errorColor)
+			t.colorCell(5, errorColor)
+		case r.Expiration == extendedTokenExpiration:
+			t.colorCell(5, warnColor)
+		}
+	}
+	t.print(color.Output)
+}
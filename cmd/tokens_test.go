@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestTokenExpirationText(t *testing.T) {
+	tests := []struct {
+		name string
+		info TokenInfo
+		want string
+	}{
+		{"default", TokenInfo{Expiration: defaultTokenExpiration}, "60m"},
+		{"extended", TokenInfo{Expiration: extendedTokenExpiration}, "60m (extended to 1y)"},
+		{"short", TokenInfo{Expiration: 10 * time.Minute}, "10m"},
+		{"day", TokenInfo{Expiration: 24 * time.Hour}, "24h"},
+		{"legacy", TokenInfo{Legacy: true, Secret: "default-token-abcde"}, "never"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenExpirationText(tt.info); got != tt.want {
+				t.Errorf("tokenExpirationText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTokenExpiry checks that sa reports the tokens the way the tokens command detects
+// them: by the Secret type when it can be read, by name otherwise.
+func TestTokenExpiry(t *testing.T) {
+	projected := func(name string, seconds *int64) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+			Sources: []corev1.VolumeProjection{{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{ExpirationSeconds: seconds}}},
+		}}}
+	}
+	secret := func(name string) corev1.Volume {
+		return corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}}}
+	}
+	clientset := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ci-credentials", Namespace: "default"}, Type: corev1.SecretTypeServiceAccountToken},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "app-token-config", Namespace: "default"}, Type: corev1.SecretTypeOpaque},
+	)
+
+	tests := []struct {
+		name    string
+		volumes []corev1.Volume
+		want    string
+	}{
+		{"no token", nil, "-"},
+		{"kube-api-access", []corev1.Volume{projected("kube-api-access-x", ptr.To[int64](3607))}, "60m (extended to 1y)"},
+		{"default expiration", []corev1.Volume{projected("token", nil)}, "60m"},
+		{"token secret by type", []corev1.Volume{secret("ci-credentials")}, "never (legacy secret)"},
+		{"opaque secret named like a token", []corev1.Volume{secret("app-token-config")}, "-"},
+		{"unreadable secret named like a token", []corev1.Volume{secret("default-token-abcde")}, "never (legacy secret)"},
+		{"several", []corev1.Volume{projected("a", ptr.To[int64](600)), secret("ci-credentials")}, "10m,never (legacy secret)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}, Spec: corev1.PodSpec{Volumes: tt.volumes}}
+			got, err := tokenExpiry(context.Background(), clientset, pod, map[string]corev1.SecretType{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("tokenExpiry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
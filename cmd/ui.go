@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// uiRefreshInterval is how often the pod list of the ui command is reloaded.
+var uiRefreshInterval time.Duration
+
+// uiCmd opens an interactive, live-updating view of the matching pods.
+var uiCmd = &cobra.Command{
+	Use:   "ui [SEARCH_PATTERN]",
+	Short: "Open a live terminal UI of pods containing [SEARCH_PATTERN] (all if omitted) to view logs, exec or describe them.",
+	RunE:  runUIFunc(configFlags),
+	// The UI takes over the terminal itself.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(uiCmd, "pods")
+	uiCmd.Flags().DurationVar(&uiRefreshInterval, "refresh", 2*time.Second, "How often the pod list is reloaded.")
+}
+
+// podUI is the state of the ui command: the latest pods and the widgets showing them.
+type podUI struct {
+	app    *tview.Application
+	table  *tview.Table
+	filter *tview.InputField
+	status *tview.TextView
+
+	mu   sync.Mutex
+	pods []corev1.Pod
+	// shown are the pods currently in the table, in row order.
+	shown []corev1.Pod
+}
+
+// runUIFunc returns a function that runs the terminal UI until the user quits.
+func runUIFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		searchTerm := ""
+		if len(args) > 0 {
+			searchTerm = args[0]
+		}
+		if uiRefreshInterval < time.Second {
+			uiRefreshInterval = time.Second
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		// Fail before switching the terminal over if the cluster can't be reached.
+		pods, err := findPods(cmd.Context(), configFlags, clientset, searchTerm)
+		if err != nil {
+			return err
+		}
+
+		ui := newPodUI()
+		ui.setPods(pods)
+		ui.bindKeys(configFlags)
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		go func() {
+			ticker := time.NewTicker(uiRefreshInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				pods, err := findPods(ctx, configFlags, clientset, searchTerm)
+				ui.app.QueueUpdateDraw(func() {
+					if err != nil {
+						ui.status.SetText(fmt.Sprintf("[red]refresh failed: %v", err))
+						return
+					}
+					ui.setPods(pods)
+				})
+			}
+		}()
+
+		return ui.app.Run()
+	}
+}
+
+// newPodUI lays out the filter box, the pod table and the key help.
+func newPodUI() *podUI {
+	ui := &podUI{
+		app:    tview.NewApplication(),
+		table:  tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		filter: tview.NewInputField().SetLabel("Filter: "),
+		status: tview.NewTextView().SetDynamicColors(true),
+	}
+	ui.table.SetBorder(true).SetTitle(" pods ")
+	ui.filter.SetChangedFunc(func(string) { ui.render() })
+	ui.filter.SetDoneFunc(func(tcell.Key) { ui.app.SetFocus(ui.table) })
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.filter, 1, 0, false).
+		AddItem(ui.table, 0, 1, true).
+		AddItem(ui.status, 1, 0, false)
+	ui.app.SetRoot(layout, true).SetFocus(ui.table)
+	return ui
+}
+
+// setPods replaces the pod list and redraws the table.
+func (ui *podUI) setPods(pods []corev1.Pod) {
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	ui.mu.Lock()
+	ui.pods = pods
+	ui.mu.Unlock()
+	ui.render()
+}
+
+// render fills the table with the pods passing the fuzzy filter, keeping the selected pod selected.
+func (ui *podUI) render() {
+	selected := ui.selectedPod()
+
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	query := ui.filter.GetText()
+	ui.shown = ui.shown[:0]
+	for _, pod := range ui.pods {
+		if fuzzyMatch(pod.Name, query) {
+			ui.shown = append(ui.shown, pod)
+		}
+	}
+
+	ui.table.Clear()
+	for col, header := range []string{"NAME", "NAMESPACE", "STATUS", "READY", "RESTARTS", "POD IP", "NODE NAME"} {
+		ui.table.SetCell(0, col, tview.NewTableCell(header).SetTextColor(tcell.ColorAqua).SetSelectable(false))
+	}
+	row := 1
+	for i, pod := range ui.shown {
+		ready, total, restarts := 0, len(pod.Status.ContainerStatuses), int32(0)
+		for _, st := range pod.Status.ContainerStatuses {
+			if st.Ready {
+				ready++
+			}
+			restarts += st.RestartCount
+		}
+		status := podStatusText(&pod)
+		statusColor := tcell.ColorGreen
+		if problem := podProblem(&pod); problem != "" {
+			statusColor = tcell.ColorRed
+		}
+		cells := []string{pod.Name, pod.Namespace, status, fmt.Sprintf("%d/%d", ready, total),
+			fmt.Sprintf("%d", restarts), pod.Status.PodIP, pod.Spec.NodeName}
+		for col, text := range cells {
+			cell := tview.NewTableCell(text)
+			if col == 2 {
+				cell.SetTextColor(statusColor)
+			}
+			ui.table.SetCell(i+1, col, cell)
+		}
+		if selected != nil && pod.Namespace == selected.Namespace && pod.Name == selected.Name {
+			row = i + 1
+		}
+	}
+	if len(ui.shown) > 0 {
+		ui.table.Select(row, 0)
+	}
+
+	ui.status.SetText(fmt.Sprintf("[aqua]%d/%d pods[-]  /:filter  l:logs  e:exec  d:describe  q:quit",
+		len(ui.shown), len(ui.pods)))
+}
+
+// selectedPod returns the pod of the selected table row, nil if there is none.
+func (ui *podUI) selectedPod() *corev1.Pod {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	row, _ := ui.table.GetSelection()
+	if row < 1 || row > len(ui.shown) {
+		return nil
+	}
+	pod := ui.shown[row-1]
+	return &pod
+}
+
+// bindKeys wires the keybindings of the table.
+func (ui *podUI) bindKeys(configFlags *genericclioptions.ConfigFlags) {
+	ui.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case '/':
+			ui.app.SetFocus(ui.filter)
+			return nil
+		case 'q':
+			ui.app.Stop()
+			return nil
+		case 'l', 'e', 'd':
+			pod := ui.selectedPod()
+			if pod == nil {
+				return nil
+			}
+			var args []string
+			wait := true
+			switch event.Rune() {
+			case 'l':
+				args = []string{"logs", "--all-containers", "--tail", "200", "-f", pod.Name}
+				wait = false
+			case 'e':
+				args = []string{"exec", "-it", pod.Name, "--", "sh", "-c", "command -v bash >/dev/null && exec bash || exec sh"}
+				wait = false
+			case 'd':
+				args = []string{"describe", "pod", pod.Name}
+			}
+			ui.runKubectl(configFlags, pod.Namespace, args, wait)
+			return nil
+		}
+		return event
+	})
+}
+
+// runKubectl suspends the UI and runs kubectl in the terminal. With wait the output stays
+// on screen until Enter is pressed; interactive commands return when they end (e.g. Ctrl+C).
+func (ui *podUI) runKubectl(configFlags *genericclioptions.ConfigFlags, namespace string, args []string, wait bool) {
+	ui.app.Suspend(func() {
+		// The terminal sends Ctrl+C to the helper too, which would end the whole UI. The
+		// signal is caught rather than ignored, since kubectl would inherit an ignored one.
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		defer signal.Stop(interrupts)
+
+		kubectl := exec.Command("kubectl", kubectlArgs(configFlags, namespace, args...)...)
+		kubectl.Stdin, kubectl.Stdout, kubectl.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := kubectl.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "kubectl %s: %v\n", args[0], err)
+			wait = true
+		}
+		if wait {
+			fmt.Print("\nPress Enter to return...")
+			bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+}
+
+// kubectlArgs prefixes args with the connection flags and namespace the helper was run with,
+// so a spawned kubectl talks to the same cluster.
+func kubectlArgs(configFlags *genericclioptions.ConfigFlags, namespace string, args ...string) []string {
+	var prefix []string
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"--kubeconfig", configFlags.KubeConfig},
+		{"--context", configFlags.Context},
+		{"--cluster", configFlags.ClusterName},
+		{"--user", configFlags.AuthInfoName},
+		{"--as", configFlags.Impersonate},
+		{"--token", configFlags.BearerToken},
+		{"--server", configFlags.APIServer},
+	} {
+		if f.value != nil && *f.value != "" {
+			prefix = append(prefix, f.name, *f.value)
+		}
+	}
+	if configFlags.ImpersonateGroup != nil {
+		for _, group := range *configFlags.ImpersonateGroup {
+			prefix = append(prefix, "--as-group", group)
+		}
+	}
+	if configFlags.Insecure != nil && *configFlags.Insecure {
+		prefix = append(prefix, "--insecure-skip-tls-verify")
+	}
+	if namespace != "" {
+		prefix = append(prefix, "-n", namespace)
+	}
+	return append(prefix, args...)
+}
+
+// fuzzyMatch reports whether the characters of query appear in name in order, ignoring case,
+// so "apiw" matches "api-worker-7d9f".
+func fuzzyMatch(name, query string) bool {
+	name, query = strings.ToLower(name), strings.ToLower(query)
+	for _, r := range query {
+		i := strings.IndexRune(name, r)
+		if i < 0 {
+			return false
+		}
+		name = name[i+1:]
+	}
+	return true
+}
+
+// podStatusText returns the status kubectl get shows: the waiting/terminated reason of the
+// first unhappy container, Terminating, or the phase.
+func podStatusText(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, st := range pod.Status.ContainerStatuses {
+		if st.State.Waiting != nil && st.State.Waiting.Reason != "" {
+			return st.State.Waiting.Reason
+		}
+		if st.State.Terminated != nil && st.State.Terminated.Reason != "" && pod.Status.Phase != corev1.PodSucceeded {
+			return st.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	return string(pod.Status.Phase)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// Build information, injected at build time with
+//
+//	go build -ldflags "-X github.com/Mahmut-Nihat/kubectl-helper/cmd.version=v1.2.0 \
+//	  -X github.com/Mahmut-Nihat/kubectl-helper/cmd.gitCommit=$(git rev-parse --short HEAD) \
+//	  -X github.com/Mahmut-Nihat/kubectl-helper/cmd.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	version   = "dev"
+	gitCommit = "unknown"
+	buildDate = "unknown"
+)
+
+// maxVersionSkew is the number of minor versions the client may be apart from the
+// server, as in the Kubernetes version skew policy for kubectl.
+const maxVersionSkew = 1
+
+// versionClientOnly skips contacting the cluster.
+var versionClientOnly bool
+
+// versionCmd prints the plugin build and the version of the connected cluster.
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the plugin version, git commit and build date, and the cluster's server version.",
+	RunE:  runVersionFunc(configFlags),
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionClientOnly, "client", false, "Only print the plugin version, don't contact the cluster.")
+}
+
+// runVersionFunc returns a function that prints the versions and warns about unsupported skew.
+func runVersionFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		clientGo := clientGoVersion()
+		fmt.Printf("Plugin version: %s\n", version)
+		fmt.Printf("Git commit:     %s\n", gitCommit)
+		fmt.Printf("Build date:     %s\n", buildDate)
+		fmt.Printf("Go version:     %s\n", runtime.Version())
+		fmt.Printf("client-go:      %s\n", clientGo)
+		if versionClientOnly {
+			return nil
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		server, err := clientset.Discovery().ServerVersion()
+		if err != nil {
+			return fmt.Errorf("failed to retrieve server version: %w", err)
+		}
+		fmt.Printf("Server version: %s\n", server.GitVersion)
+
+		clientMinor, ok1 := minorVersion(clientGo)
+		serverMinor, ok2 := minorVersion(server.GitVersion)
+		if !ok1 || !ok2 {
+			return nil
+		}
+		// client-go v0.N.x is the client library of Kubernetes 1.N.
+		if skew := clientMinor - serverMinor; skew > maxVersionSkew || skew < -maxVersionSkew {
+			color.New(color.FgYellow).Printf("WARNING: client-go %s (Kubernetes 1.%d) and server %s are %d minor versions apart, more than the supported %d\n",
+				clientGo, clientMinor, server.GitVersion, max(skew, -skew), maxVersionSkew)
+		}
+		return nil
+	}
+}
+
+// clientGoVersion returns the version of k8s.io/client-go the plugin was built with.
+func clientGoVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "k8s.io/client-go" {
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// minorVersion extracts the minor number of a "v1.29.3" or "v0.29.3" style version.
+func minorVersion(v string) (int, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(v, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, false
+	}
+	// Providers append their own suffix, e.g. "29+" on EKS.
+	minor, err := strconv.Atoi(strings.TrimRight(parts[1], "+"))
+	if err != nil {
+		return 0, false
+	}
+	return minor, true
+}
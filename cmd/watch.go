@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PodChange is one change of a watched pod.
+type PodChange struct {
+	Time      time.Time `json:"time"`
+	Kind      string    `json:"kind"`
+	Pod       string    `json:"pod"`
+	Namespace string    `json:"namespace"`
+	Message   string    `json:"message"`
+}
+
+// podChangeKinds are the change kinds --notify-on accepts.
+var podChangeKinds = []string{"added", "deleted", "phase", "ip", "restart", "crashloop"}
+
+// watch command flags.
+var (
+	watchNotifyURL string
+	watchNotifyOn  []string
+)
+
+// watchCmd prints the changes of the matching pods as they happen.
+var watchCmd = &cobra.Command{
+	Use:   "watch [SEARCH_PATTERN]",
+	Short: "Watch pods containing [SEARCH_PATTERN] and report restarts, IP changes and CrashLoopBackOff as they happen.",
+	RunE:  runWatchFunc(configFlags),
+	// Changes have to show up as they happen, not when the watch ends.
+	Annotations: map[string]string{pagerAnnotation: "false"},
+}
+
+func init() {
+	addNamespaceFlag(watchCmd, "pods")
+	watchCmd.Flags().StringVar(&watchNotifyURL, "notify-url", "",
+		"Also POST every change to this webhook. Slack incoming webhooks get a Slack message, other URLs a JSON object.")
+	watchCmd.Flags().StringSliceVar(&watchNotifyOn, "notify-on", []string{"restart", "ip", "crashloop"},
+		"Change kinds sent to --notify-url. Any of: "+strings.Join(podChangeKinds, ", ")+".")
+}
+
+// runWatchFunc returns a function that watches the matching pods until interrupted.
+func runWatchFunc(configFlags *genericclioptions.ConfigFlags) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("please provide a search pattern, for example:\n  kubectl helper watch api\nor:\n  kubectl helper watch -n dev api --notify-url https://hooks.slack.com/services/...")
+		}
+		searchTerm := args[0]
+
+		notifyOn := map[string]bool{}
+		for _, kind := range watchNotifyOn {
+			valid := false
+			for _, k := range podChangeKinds {
+				valid = valid || k == kind
+			}
+			if !valid {
+				return fmt.Errorf("unknown --notify-on kind %q, must be any of: %s", kind, strings.Join(podChangeKinds, ", "))
+			}
+			notifyOn[kind] = true
+		}
+
+		clientset, err := newClientset(configFlags)
+		if err != nil {
+			return err
+		}
+		namespaces, err := targetNamespaces(configFlags)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		changes := make(chan PodChange, 100)
+		report := func(changed []PodChange) {
+			for _, c := range changed {
+				changes <- c
+			}
+		}
+		handler := cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj interface{}, isInInitialList bool) {
+				pod, ok := obj.(*corev1.Pod)
+				// Pods that existed before the watch started aren't news.
+				if !ok || isInInitialList || !matchesPattern(pod.Name, searchTerm) {
+					return
+				}
+				report([]PodChange{newPodChange(pod, "added", fmt.Sprintf("pod added on node %s", nonEmptyOr(pod.Spec.NodeName, "<not scheduled>")))})
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldPod, ok1 := oldObj.(*corev1.Pod)
+				newPod, ok2 := newObj.(*corev1.Pod)
+				if !ok1 || !ok2 || !matchesPattern(newPod.Name, searchTerm) {
+					return
+				}
+				report(diffPods(oldPod, newPod))
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				pod, ok := obj.(*corev1.Pod)
+				if !ok || !matchesPattern(pod.Name, searchTerm) {
+					return
+				}
+				report([]PodChange{newPodChange(pod, "deleted", "pod deleted")})
+			},
+		}
+
+		var synced []cache.InformerSynced
+		for _, ns := range namespaces {
+			factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0, informers.WithNamespace(ns))
+			informer := factory.Core().V1().Pods().Informer()
+			if _, err := informer.AddEventHandler(handler); err != nil {
+				return fmt.Errorf("failed to watch pods: %w", err)
+			}
+			synced = append(synced, informer.HasSynced)
+			factory.Start(ctx.Done())
+		}
+		if !cache.WaitForCacheSync(ctx.Done(), synced...) {
+			return fmt.Errorf("failed to sync the pod cache")
+		}
+		color.New(color.FgCyan).Fprintf(os.Stderr, "Watching pods matching %q (Ctrl+C to stop)...\n", searchTerm)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case c := <-changes:
+				printPodChange(c)
+				if watchNotifyURL != "" && notifyOn[c.Kind] {
+					if err := notifyPodChange(ctx, watchNotifyURL, c); err != nil {
+						color.New(color.FgYellow).Fprintf(os.Stderr, "notification failed: %v\n", err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// newPodChange builds a change of pod happening now.
+func newPodChange(pod *corev1.Pod, kind, message string) PodChange {
+	return PodChange{Time: time.Now(), Kind: kind, Pod: pod.Name, Namespace: pod.Namespace, Message: message}
+}
+
+// nonEmptyOr returns s, or fallback when s is empty.
+func nonEmptyOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// diffPods returns the interesting changes between two versions of a pod.
+func diffPods(oldPod, newPod *corev1.Pod) []PodChange {
+	var changes []PodChange
+	if oldPod.Status.Phase != newPod.Status.Phase {
+		changes = append(changes, newPodChange(newPod, "phase",
+			fmt.Sprintf("phase %s -> %s", nonEmptyOr(string(oldPod.Status.Phase), "<none>"), newPod.Status.Phase)))
+	}
+	if oldPod.Status.PodIP != newPod.Status.PodIP {
+		changes = append(changes, newPodChange(newPod, "ip",
+			fmt.Sprintf("IP %s -> %s", nonEmptyOr(oldPod.Status.PodIP, "<none>"), nonEmptyOr(newPod.Status.PodIP, "<none>"))))
+	}
+
+	oldStatuses := map[string]corev1.ContainerStatus{}
+	for _, st := range oldPod.Status.ContainerStatuses {
+		oldStatuses[st.Name] = st
+	}
+	for _, st := range newPod.Status.ContainerStatuses {
+		old := oldStatuses[st.Name]
+		if st.RestartCount > old.RestartCount {
+			reason := ""
+			if term := st.LastTerminationState.Terminated; term != nil {
+				reason = fmt.Sprintf(" (%s, exit code %d)", term.Reason, term.ExitCode)
+			}
+			changes = append(changes, newPodChange(newPod, "restart",
+				fmt.Sprintf("container %s restarted, %d restart(s)%s", st.Name, st.RestartCount, reason)))
+		}
+		if isCrashLooping(st) && !isCrashLooping(old) {
+			changes = append(changes, newPodChange(newPod, "crashloop",
+				fmt.Sprintf("container %s is in CrashLoopBackOff", st.Name)))
+		}
+	}
+	return changes
+}
+
+// isCrashLooping reports whether the container is waiting in CrashLoopBackOff.
+func isCrashLooping(st corev1.ContainerStatus) bool {
+	return st.State.Waiting != nil && st.State.Waiting.Reason == "CrashLoopBackOff"
+}
+
+// printPodChange prints one change, colored by how bad it is.
+func printPodChange(c PodChange) {
+	kindColor := color.New(color.FgCyan)
+	switch c.Kind {
+	case "crashloop", "deleted":
+		kindColor = color.New(color.FgRed, color.Bold)
+	case "restart", "ip":
+		kindColor = color.New(color.FgYellow)
+	case "added":
+		kindColor = color.New(color.FgGreen)
+	}
+	fmt.Printf("%s ", c.Time.Format("15:04:05"))
+	kindColor.Printf("%-10s", strings.ToUpper(c.Kind))
+	fmt.Printf(" %s/%s: %s\n", c.Namespace, c.Pod, c.Message)
+}
+
+// notifyPodChange posts the change to a webhook. Slack incoming webhooks only accept
+// their own message format; everything else gets the change as JSON.
+func notifyPodChange(ctx context.Context, notifyURL string, c PodChange) error {
+	var payload interface{} = c
+	if u, err := url.Parse(notifyURL); err == nil && u.Host == "hooks.slack.com" {
+		payload = map[string]string{
+			"text": fmt.Sprintf(":rotating_light: *%s* `%s/%s`: %s", strings.ToUpper(c.Kind), c.Namespace, c.Pod, c.Message),
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}